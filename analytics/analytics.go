@@ -0,0 +1,117 @@
+// Package analytics summarizes how players answer, so teachers can see which
+// fallacies get mistaken for which.
+package analytics
+
+import (
+	"encoding/csv"
+	"github.com/dkeriazisStuy/FallacyQuest/profile"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// None is the column for checks made without choosing a fallacy.
+const None = "none"
+
+// Matrix is a confusion matrix over fallacy keys: how many times each
+// fallacy was chosen when each was right. The diagonal holds right choices.
+type Matrix struct {
+	Keys   []string
+	counts map[string]map[string]int
+}
+
+// NewMatrix returns an empty matrix over keys.
+func NewMatrix(keys []string) *Matrix {
+	return &Matrix{Keys: keys, counts: make(map[string]map[string]int)}
+}
+
+// Add counts one check where chosen was picked and answer was right. An empty
+// chosen counts under None.
+func (m *Matrix) Add(answer, chosen string) {
+	if chosen == "" {
+		chosen = None
+	}
+	row := m.counts[answer]
+	if row == nil {
+		row = make(map[string]int)
+		m.counts[answer] = row
+	}
+	row[chosen] += 1
+}
+
+// AddProfile counts every attempt logged in p.
+func (m *Matrix) AddProfile(p *profile.Profile) {
+	for _, a := range p.Attempts {
+		m.Add(a.Fallacy, a.Chosen)
+	}
+}
+
+// Count is how many times chosen was picked when answer was right.
+func (m *Matrix) Count(answer, chosen string) int {
+	return m.counts[answer][chosen]
+}
+
+// Total is how many checks were made of questions about answer.
+func (m *Matrix) Total(answer string) int {
+	n := 0
+	for _, count := range m.counts[answer] {
+		n += count
+	}
+	return n
+}
+
+// Max is the largest count off the diagonal, for scaling a display.
+func (m *Matrix) Max() int {
+	max := 0
+	for answer, row := range m.counts {
+		for chosen, count := range row {
+			if chosen != answer && count > max {
+				max = count
+			}
+		}
+	}
+	return max
+}
+
+// Pair is a fallacy mistaken for another.
+type Pair struct {
+	Answer, Chosen string
+	Count          int
+}
+
+// Confused returns up to n of the most frequent mix ups between the matrix's
+// keys, most frequent first.
+func (m *Matrix) Confused(n int) []Pair {
+	var pairs []Pair
+	for _, answer := range m.Keys {
+		for _, chosen := range m.Keys {
+			if count := m.Count(answer, chosen); chosen != answer && count > 0 {
+				pairs = append(pairs, Pair{answer, chosen, count})
+			}
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i].Count > pairs[j].Count
+	})
+	if len(pairs) > n {
+		pairs = pairs[:n]
+	}
+	return pairs
+}
+
+// WriteCSV writes the matrix with a row per right answer and a column per
+// choice, ending with the None column.
+func (m *Matrix) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	columns := append(append([]string(nil), m.Keys...), None)
+	cw.Write(append([]string{"answer"}, columns...))
+	for _, answer := range m.Keys {
+		record := []string{answer}
+		for _, chosen := range columns {
+			record = append(record, strconv.Itoa(m.Count(answer, chosen)))
+		}
+		cw.Write(record)
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,48 @@
+package analytics
+
+import (
+	"github.com/dkeriazisStuy/FallacyQuest/profile"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMatrix(t *testing.T) {
+	m := NewMatrix([]string{"post", "cum", "straw"})
+	p := profile.New("Ann")
+	for _, a := range []profile.Attempt{
+		{Fallacy: "post", Chosen: "cum"},
+		{Fallacy: "post", Chosen: "cum"},
+		{Fallacy: "post", Chosen: "post"},
+		{Fallacy: "cum", Chosen: "post"},
+		{Fallacy: "straw", Chosen: ""},
+		{Fallacy: "straw", Chosen: "unknown"},
+	} {
+		p.AddAttempt(a)
+	}
+	m.AddProfile(p)
+	m.Add("cum", "cum")
+	if m.Count("post", "cum") != 2 || m.Count("straw", None) != 1 || m.Total("post") != 3 || m.Total("straw") != 2 {
+		t.Errorf("counts %v", m.counts)
+	}
+	// The diagonal and unknown keys don't count as mix ups.
+	if m.Max() != 2 {
+		t.Errorf("Max %d, want 2", m.Max())
+	}
+	want := []Pair{{"post", "cum", 2}, {"cum", "post", 1}}
+	if got := m.Confused(5); !reflect.DeepEqual(got, want) {
+		t.Errorf("Confused got %v, want %v", got, want)
+	}
+	if got := m.Confused(1); !reflect.DeepEqual(got, want[:1]) {
+		t.Errorf("Confused(1) got %v", got)
+	}
+
+	var b strings.Builder
+	if err := m.WriteCSV(&b); err != nil {
+		t.Fatal(err)
+	}
+	csv := "answer,post,cum,straw,none\npost,1,2,0,0\ncum,1,1,0,0\nstraw,0,0,0,1\n"
+	if b.String() != csv {
+		t.Errorf("got CSV\n%s\nwant\n%s", b.String(), csv)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"github.com/dkeriazisStuy/FallacyQuest/input"
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+)
+
+// blitzLengths are the round lengths offered, in seconds.
+var blitzLengths = []int{60, 120, 300}
+
+// blitzMenu picks how long a Blitz round lasts.
+type blitzMenu struct {
+	st       *sceneStack
+	lengths  []button
+	back     button
+	backIcon *imdraw.IMDraw
+	focus    focusList
+	// Text
+	titleTxt, infoTxt *text.Text
+	lengthTxts        []*text.Text
+}
+
+func newBlitzMenu(st *sceneStack) *blitzMenu {
+	return &blitzMenu{st: st}
+}
+
+func (b *blitzMenu) enter() {
+	b.titleTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprint(b.titleTxt, "Blitz")
+	b.infoTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprint(b.infoTxt, "Answer as many as you can before time runs out.\nQuick answers earn a bonus.")
+	b.lengthTxts = nil
+	for _, seconds := range blitzLengths {
+		txt := text.New(pixel.ZV, atlas)
+		fmt.Fprintf(txt, "%d:%02d", seconds/60, seconds%60)
+		b.lengthTxts = append(b.lengthTxts, txt)
+	}
+}
+
+func (b *blitzMenu) exit() {}
+
+func (b *blitzMenu) onResize() {
+	b.back, b.backIcon = backButton(b.st.win)
+	b.lengths = nil
+	for i := range blitzLengths {
+		pos := pixel.V(winX/2, (5.5-1.5*float64(i))*winY/11)
+		b.lengths = append(b.lengths, newButton(b.st.win, centeredRect(pos, 100, 40), colornames.Sandybrown, colornames.Rosybrown))
+	}
+}
+
+func (b *blitzMenu) update(dt float64) {
+	var focusable []*button
+	for i := range b.lengths {
+		focusable = append(focusable, &b.lengths[i])
+	}
+	b.focus.update(focusable...)
+	if b.back.check() || in.JustPressed(input.Back) {
+		b.st.pop()
+		return
+	}
+	for i := range b.lengths {
+		if b.lengths[i].check() {
+			b.st.replace(newBlitzScene(b.st, blitzLengths[i]))
+			return
+		}
+	}
+}
+
+func (b *blitzMenu) draw() {
+	win := b.st.win
+	drawText(win, b.titleTxt, pixel.V(winX/2, 8.5*winY/11), 5)
+	drawText(win, b.infoTxt, pixel.V(winX/2, 7*winY/11), 2)
+	for i := range b.lengths {
+		b.lengths[i].draw()
+		drawText(win, b.lengthTxts[i], b.lengths[i].rect.Center(), 3)
+	}
+	// Back
+	b.back.draw()
+	b.backIcon.Draw(win)
+}
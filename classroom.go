@@ -0,0 +1,236 @@
+package main
+
+import (
+	"fmt"
+	"github.com/dkeriazisStuy/FallacyQuest/classroom"
+	"github.com/dkeriazisStuy/FallacyQuest/dashboard"
+	"github.com/dkeriazisStuy/FallacyQuest/input"
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+	"net"
+	"strings"
+)
+
+const (
+	// classroomQuestions is the length of a classroom round.
+	classroomQuestions = 10
+	leaderboardRows    = 10
+)
+
+// hostAddr and joinAddr are the classroom addresses given on the command
+// line, if any.
+var hostAddr, joinAddr string
+
+// lanAddrs lists the addresses students can reach port on.
+func lanAddrs(port int) []string {
+	var addrs []string
+	ifaces, _ := net.InterfaceAddrs()
+	for _, a := range ifaces {
+		if ip, ok := a.(*net.IPNet); ok && !ip.IP.IsLoopback() && ip.IP.To4() != nil {
+			addrs = append(addrs, fmt.Sprintf("%s:%d", ip.IP, port))
+		}
+	}
+	return addrs
+}
+
+// writeLeaderboard lists entries, best first.
+func writeLeaderboard(txt *text.Text, entries []classroom.Entry) {
+	txt.Clear()
+	if len(entries) == 0 {
+		fmt.Fprint(txt, "Nobody has joined yet")
+	}
+	for i, e := range entries {
+		if i == leaderboardRows {
+			fmt.Fprintf(txt, "... and %d more", len(entries)-i)
+			break
+		}
+		status := ""
+		switch {
+		case !e.Connected:
+			status = "  (left)"
+		case e.Playing:
+			status = "  (playing)"
+		}
+		fmt.Fprintf(txt, "%2d. %-16s %8.2f  %d/%d%s\n", i+1, e.Name, e.Score, e.Correct, e.Answered, status)
+	}
+}
+
+// hostScene runs a class: students join over the network, the teacher
+// starts rounds and everyone's scores come back to the leaderboard.
+type hostScene struct {
+	st       *sceneStack
+	host     *classroom.Host
+	rounds   int
+	err      error
+	start    button
+	back     button
+	backIcon *imdraw.IMDraw
+	focus    focusList
+	// Text
+	titleTxt, addrTxt, boardTxt, startTxt, statusTxt *text.Text
+}
+
+func newHostScene(st *sceneStack) *hostScene {
+	return &hostScene{st: st}
+}
+
+func (h *hostScene) enter() {
+	h.titleTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprint(h.titleTxt, "Classroom")
+	h.addrTxt = text.New(pixel.ZV, atlas)
+	h.boardTxt = text.New(pixel.ZV, atlas)
+	h.startTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprint(h.startTxt, "Start round")
+	h.statusTxt = text.New(pixel.ZV, atlas)
+	h.statusTxt.Color = colornames.Yellow
+	b := board
+	if b == nil {
+		b = dashboard.NewBoard(catalog, questions)
+	}
+	h.host, h.err = classroom.Listen(hostAddr, b)
+	if h.err != nil {
+		fmt.Fprint(h.statusTxt, h.err)
+		return
+	}
+	addrs := []string{h.host.Addr().String()}
+	if tcp, ok := h.host.Addr().(*net.TCPAddr); ok && tcp.IP.IsUnspecified() {
+		addrs = lanAddrs(tcp.Port)
+	}
+	fmt.Fprintf(h.addrTxt, "Students join with -join %s", strings.Join(addrs, " or "))
+}
+
+func (h *hostScene) exit() {
+	if h.host != nil {
+		h.host.Close()
+	}
+}
+
+func (h *hostScene) onResize() {
+	win := h.st.win
+	h.back, h.backIcon = backButton(win)
+	h.start = newButton(win, centeredRect(pixel.V(winX/2, winY*80/origY), 120, 30), colornames.Green, colornames.Darkgreen)
+}
+
+func (h *hostScene) update(dt float64) {
+	h.focus.update(&h.start)
+	if h.back.check() || in.JustPressed(input.Back) {
+		h.st.pop()
+		return
+	}
+	if h.host == nil {
+		return
+	}
+	if h.start.check() {
+		r, err := classroom.NewRound(catalog, questions, classroomQuestions, settings.Scoring, newSeed())
+		h.statusTxt.Clear()
+		if err != nil {
+			fmt.Fprint(h.statusTxt, err)
+			return
+		}
+		h.host.Start(r)
+		h.rounds += 1
+		fmt.Fprintf(h.statusTxt, "Round %d sent to %d students", h.rounds, h.host.Clients())
+	}
+	writeLeaderboard(h.boardTxt, h.host.Leaderboard())
+}
+
+func (h *hostScene) draw() {
+	win := h.st.win
+	drawText(win, h.titleTxt, pixel.V(winX/2, winY*715/origY), 4)
+	drawText(win, h.addrTxt, pixel.V(winX/2, winY*660/origY), 1.5)
+	h.boardTxt.Color = colornames.White
+	drawText(win, h.boardTxt, pixel.V(winX/2, winY*420/origY), 2)
+	drawText(win, h.statusTxt, pixel.V(winX/2, winY*140/origY), 1.5)
+	if h.host != nil {
+		h.start.draw()
+		drawText(win, h.startTxt, h.start.rect.Center(), 2)
+	}
+	// Back
+	h.back.draw()
+	h.backIcon.Draw(win)
+}
+
+// joinScene is a student's lobby: it connects to the host, starts each
+// round the host sends and shows the leaderboard in between.
+type joinScene struct {
+	st       *sceneStack
+	client   *classroom.Client
+	rounds   int
+	back     button
+	backIcon *imdraw.IMDraw
+	// Text
+	titleTxt, statusTxt, boardTxt *text.Text
+}
+
+func newJoinScene(st *sceneStack) *joinScene {
+	return &joinScene{st: st}
+}
+
+func (j *joinScene) enter() {
+	j.titleTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprint(j.titleTxt, "Classroom")
+	j.statusTxt = text.New(pixel.ZV, atlas)
+	j.boardTxt = text.New(pixel.ZV, atlas)
+	name := defaultPlayer
+	if player != nil {
+		name = player.Name
+	}
+	var err error
+	j.client, err = classroom.Dial(joinAddr, name, catalog, questions)
+	if err != nil {
+		j.statusTxt.Color = colornames.Yellow
+		fmt.Fprint(j.statusTxt, err)
+		return
+	}
+	// A round already under way when joining is left to the next one.
+	_, j.rounds = j.client.Round()
+}
+
+func (j *joinScene) exit() {
+	if j.client != nil {
+		j.client.Close()
+	}
+}
+
+func (j *joinScene) onResize() {
+	j.back, j.backIcon = backButton(j.st.win)
+}
+
+func (j *joinScene) update(dt float64) {
+	if j.back.check() || in.JustPressed(input.Back) {
+		j.st.pop()
+		return
+	}
+	if j.client == nil {
+		return
+	}
+	j.statusTxt.Clear()
+	if err := j.client.Err(); err != nil {
+		j.statusTxt.Color = colornames.Yellow
+		fmt.Fprintf(j.statusTxt, "Disconnected: %v", err)
+		return
+	}
+	fmt.Fprintf(j.statusTxt, "Joined %s. Waiting for the teacher to start a round", joinAddr)
+	writeLeaderboard(j.boardTxt, j.client.Leaderboard())
+	if r, n := j.client.Round(); n > j.rounds && r != nil {
+		j.rounds = n
+		q := newQuizScene(j.st, modeClassroom)
+		q.round = r
+		q.client = j.client
+		q.total = len(r.Questions)
+		j.st.push(q)
+	}
+}
+
+func (j *joinScene) draw() {
+	win := j.st.win
+	drawText(win, j.titleTxt, pixel.V(winX/2, winY*715/origY), 4)
+	drawText(win, j.statusTxt, pixel.V(winX/2, winY*660/origY), 1.5)
+	j.boardTxt.Color = colornames.White
+	drawText(win, j.boardTxt, pixel.V(winX/2, winY*400/origY), 2)
+	// Back
+	j.back.draw()
+	j.backIcon.Draw(win)
+}
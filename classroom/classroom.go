@@ -0,0 +1,122 @@
+package classroom
+
+import (
+	"bufio"
+	"encoding/json"
+	"github.com/dkeriazisStuy/FallacyQuest/content"
+	"github.com/dkeriazisStuy/FallacyQuest/events"
+	"github.com/dkeriazisStuy/FallacyQuest/quest"
+	"io"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+)
+
+// Version is the protocol spoken by this package.
+const Version = 1
+
+const (
+	// helloTimeout is how long either side waits for the other to say who
+	// it is.
+	helloTimeout = 10 * time.Second
+	// writeTimeout gives up on a peer that has stopped reading.
+	writeTimeout = 5 * time.Second
+)
+
+// Message is everything sent either way. Type says which fields are set.
+type Message struct {
+	Type        string         `json:"type"`
+	Version     int            `json:"version,omitempty"`
+	Name        string         `json:"name,omitempty"`
+	ID          string         `json:"id,omitempty"`
+	Error       string         `json:"error,omitempty"`
+	Round       *Round         `json:"round,omitempty"`
+	Event       *events.Record `json:"event,omitempty"`
+	Leaderboard []Entry        `json:"leaderboard,omitempty"`
+}
+
+// Round is the questions the whole class answers, in order, and how they
+// are scored.
+type Round struct {
+	Seed      int64               `json:"seed"`
+	Scoring   quest.ScoringConfig `json:"scoring"`
+	Questions []quest.Item        `json:"questions"`
+}
+
+// NewRound deals n questions from questions, each with its choices.
+func NewRound(cat *content.Catalog, questions []content.Question, n int, scoring quest.ScoringConfig, seed int64) (*Round, error) {
+	rng := rand.New(rand.NewSource(seed))
+	deck, err := quest.NewDeck(cat, questions, quest.Filter{}, rng)
+	if err != nil {
+		return nil, err
+	}
+	r := &Round{Seed: seed, Scoring: scoring}
+	for i := 0; i < n; i++ {
+		q := deck.Deal()
+		r.Questions = append(r.Questions, quest.Item{Question: q.ID(), Fallacy: q.Name, Choices: quest.FallacyChoices(rng, cat, q.Name)})
+	}
+	return r, nil
+}
+
+// Check reports whether the round can be played with cat and questions:
+// its scorer must be known, and every question must be in the bank with
+// the same answer, offering that answer and only fallacies in the catalog.
+func (r *Round) Check(cat *content.Catalog, questions []content.Question) error {
+	if _, err := r.Scoring.NewScorer(); err != nil {
+		return err
+	}
+	return r.Sequence().Check(cat, questions)
+}
+
+// Sequence is the picker and distractor that play the round.
+func (r *Round) Sequence() *quest.Sequence {
+	return &quest.Sequence{Items: r.Questions}
+}
+
+// Entry is one player's line on the leaderboard, for the round they are
+// playing or last played.
+type Entry struct {
+	Name      string  `json:"name"`
+	Score     float64 `json:"score"`
+	Answered  int     `json:"answered"`
+	Correct   int     `json:"correct"`
+	Playing   bool    `json:"playing"`
+	Connected bool    `json:"connected"`
+}
+
+// conn sends and receives messages over one connection. Sends may come
+// from any goroutine.
+type conn struct {
+	c   net.Conn
+	r   *bufio.Scanner
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func newConn(c net.Conn) *conn {
+	r := bufio.NewScanner(c)
+	r.Buffer(nil, 1<<20)
+	return &conn{c: c, r: r, enc: json.NewEncoder(c)}
+}
+
+func (c *conn) send(m Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.c.SetWriteDeadline(time.Now().Add(writeTimeout))
+	return c.enc.Encode(m)
+}
+
+// receive reads the next message, waiting until deadline if it isn't zero.
+func (c *conn) receive(deadline time.Time) (Message, error) {
+	c.c.SetReadDeadline(deadline)
+	var m Message
+	if !c.r.Scan() {
+		err := c.r.Err()
+		if err == nil {
+			err = io.EOF
+		}
+		return m, err
+	}
+	return m, json.Unmarshal(c.r.Bytes(), &m)
+}
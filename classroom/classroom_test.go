@@ -0,0 +1,274 @@
+package classroom
+
+import (
+	"bufio"
+	"encoding/json"
+	"github.com/dkeriazisStuy/FallacyQuest/content"
+	"github.com/dkeriazisStuy/FallacyQuest/dashboard"
+	"github.com/dkeriazisStuy/FallacyQuest/events"
+	"github.com/dkeriazisStuy/FallacyQuest/quest"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func builtin(t *testing.T) (*content.Catalog, []content.Question) {
+	t.Helper()
+	cat, err := content.LoadCatalog(content.Builtin())
+	if err != nil {
+		t.Fatal(err)
+	}
+	questions, err := content.Load(content.Builtin(), cat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cat, questions
+}
+
+func listen(t *testing.T) (*Host, *content.Catalog, []content.Question) {
+	t.Helper()
+	cat, questions := builtin(t)
+	h, err := Listen("127.0.0.1:0", dashboard.NewBoard(cat, questions))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { h.Close() })
+	return h, cat, questions
+}
+
+func dial(t *testing.T, h *Host, name string, cat *content.Catalog, questions []content.Question) *Client {
+	t.Helper()
+	cl, err := Dial(h.Addr().String(), name, cat, questions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { cl.Close() })
+	return cl
+}
+
+// eventually waits for ok to hold.
+func eventually(t *testing.T, what string, ok func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !ok() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func newRound(t *testing.T, cat *content.Catalog, questions []content.Question) *Round {
+	t.Helper()
+	r, err := NewRound(cat, questions, 5, quest.DefaultScoring(), 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+// play sends the events of a round in which the first right answers are
+// right and the rest wrong, scoring 10 for each right one.
+func play(cl *Client, r *Round, right int) {
+	seq := 0
+	send := func(e events.Event) {
+		seq += 1
+		cl.Handle(events.Record{Seq: seq, Time: time.Now(), Event: e})
+	}
+	send(events.SessionStart{Mode: "classroom", Seed: r.Seed, Length: len(r.Questions)})
+	score := 0.0
+	for i, item := range r.Questions {
+		send(events.QuestionShown{Number: i + 1, Question: item.Question, Fallacy: item.Fallacy, Choices: item.Choices})
+		correct := i < right
+		if correct {
+			score += 10
+		}
+		send(events.Answered{Question: item.Question, Fallacy: item.Fallacy, Correct: correct, Points: 10, Score: score})
+	}
+	send(events.SessionEnd{Score: score, Questions: len(r.Questions), Finished: true})
+}
+
+func TestLoopback(t *testing.T) {
+	h, cat, questions := listen(t)
+	ann := dial(t, h, "Ann", cat, questions)
+	bob := dial(t, h, "Bob", cat, questions)
+	if ann.ID == bob.ID {
+		t.Fatalf("both clients got ID %q", ann.ID)
+	}
+	eventually(t, "both clients to join", func() bool { return h.Clients() == 2 })
+
+	r := newRound(t, cat, questions)
+	h.Start(r)
+	for _, cl := range []*Client{ann, bob} {
+		eventually(t, "the round", func() bool {
+			_, n := cl.Round()
+			return n == 1
+		})
+		if got, _ := cl.Round(); !reflect.DeepEqual(got, r) {
+			t.Errorf("%s got round %+v, want %+v", cl.ID, got, r)
+		}
+	}
+
+	play(ann, r, 2)
+	play(bob, r, 4)
+	want := []Entry{
+		{Name: "Bob", Score: 40, Answered: 5, Correct: 4, Connected: true},
+		{Name: "Ann", Score: 20, Answered: 5, Correct: 2, Connected: true},
+	}
+	eventually(t, "the host's leaderboard", func() bool { return reflect.DeepEqual(h.Leaderboard(), want) })
+	for _, cl := range []*Client{ann, bob} {
+		eventually(t, cl.ID+"'s leaderboard", func() bool { return reflect.DeepEqual(cl.Leaderboard(), want) })
+	}
+
+	// Someone who joins late gets the round under way.
+	cy := dial(t, h, "Cy", cat, questions)
+	if got, n := cy.Round(); n != 1 || !reflect.DeepEqual(got, r) {
+		t.Errorf("late joiner got round %d: %+v", n, got)
+	}
+
+	// Leaving keeps the score on the board.
+	bob.Close()
+	eventually(t, "Bob to leave", func() bool {
+		lb := h.Leaderboard()
+		return len(lb) == 3 && lb[0].Name == "Bob" && !lb[0].Connected
+	})
+	eventually(t, "Ann to hear Bob left", func() bool {
+		lb := ann.Leaderboard()
+		return len(lb) == 3 && !lb[0].Connected
+	})
+	if err := ann.Err(); err != nil {
+		t.Errorf("Ann's connection: %v", err)
+	}
+}
+
+func TestVersionMismatch(t *testing.T) {
+	h, _, _ := listen(t)
+	c, err := net.Dial("tcp", h.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	json.NewEncoder(c).Encode(Message{Type: "hello", Version: Version + 1, Name: "Future"})
+	c.SetReadDeadline(time.Now().Add(5 * time.Second))
+	r := bufio.NewScanner(c)
+	if !r.Scan() {
+		t.Fatalf("no reply: %v", r.Err())
+	}
+	var m Message
+	if err := json.Unmarshal(r.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.Type != "error" || !strings.Contains(m.Error, "version") {
+		t.Errorf("got %+v, want a version error", m)
+	}
+	if r.Scan() {
+		t.Errorf("host kept talking: %s", r.Bytes())
+	}
+	if h.Clients() != 0 || len(h.Leaderboard()) != 0 {
+		t.Error("the client was let in")
+	}
+}
+
+func TestHandleDoesNotWait(t *testing.T) {
+	cat, questions := builtin(t)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	// The host welcomes the client and then never reads.
+	go func() {
+		c, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		json.NewEncoder(c).Encode(Message{Type: "welcome", Version: Version, ID: "1"})
+		time.Sleep(10 * time.Second)
+	}()
+	cl, err := Dial(ln.Addr().String(), "Ann", cat, questions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+
+	big := strings.Repeat("x", 1<<16)
+	start := time.Now()
+	for i := 0; i < 2*sendQueue; i++ {
+		cl.Handle(events.Record{Seq: i + 1, Event: events.QuestionShown{Number: i + 1, Question: big}})
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("handling events took %v", d)
+	}
+}
+
+func TestBadRound(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		spoil func(r *Round)
+		want  string
+	}{
+		{"scorer", func(r *Round) { r.Scoring.Scorer = "golf" }, "golf"},
+		{"unknown question", func(r *Round) { r.Questions[1].Question = "0123456789abcdef" }, "0123456789abcdef"},
+	} {
+		h, cat, questions := listen(t)
+		cl := dial(t, h, "Ann", cat, questions)
+		eventually(t, "the client to join", func() bool { return h.Clients() == 1 })
+		r := newRound(t, cat, questions)
+		tt.spoil(r)
+		h.Start(r)
+		eventually(t, tt.name+" to be refused", func() bool { return cl.Err() != nil })
+		if _, n := cl.Round(); n != 0 {
+			t.Errorf("%s: client took a round it can't play", tt.name)
+		}
+		if !strings.Contains(cl.Err().Error(), tt.want) {
+			t.Errorf("%s: got %v", tt.name, cl.Err())
+		}
+
+		// A round under way that can't be played keeps others from joining.
+		if _, err := Dial(h.Addr().String(), "Bob", cat, questions); err == nil {
+			t.Errorf("%s: joined with a round that can't be played", tt.name)
+		}
+	}
+}
+
+func TestRoundCheck(t *testing.T) {
+	cat, questions := builtin(t)
+	r := newRound(t, cat, questions)
+	if err := r.Check(cat, questions); err != nil {
+		t.Fatalf("a dealt round: %v", err)
+	}
+	for name, spoil := range map[string]func(r *Round){
+		"scorer":           func(r *Round) { r.Scoring.Scorer = "golf" },
+		"unknown choice":   func(r *Round) { r.Questions[2].Choices[1] = "nope" },
+		"unknown question": func(r *Round) { r.Questions[3].Question = "0123456789abcdef" },
+		"wrong answer": func(r *Round) {
+			item := &r.Questions[4]
+			for _, choice := range item.Choices {
+				if choice != item.Fallacy {
+					item.Fallacy = choice
+					break
+				}
+			}
+		},
+		"no answer": func(r *Round) {
+			item := &r.Questions[0]
+			item.Choices = item.Choices[:0]
+		},
+	} {
+		bad := newRound(t, cat, questions)
+		spoil(bad)
+		if err := bad.Check(cat, questions); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+
+	// Sessions drop fallacies their catalog doesn't have.
+	item := r.Questions[0]
+	seq := &quest.Sequence{Items: []quest.Item{{Question: item.Question, Fallacy: item.Fallacy, Choices: append([]string{"nope"}, item.Choices...)}}}
+	if got := seq.Choices(nil, cat, item.Fallacy); !reflect.DeepEqual(got, item.Choices) {
+		t.Errorf("got choices %v, want %v", got, item.Choices)
+	}
+}
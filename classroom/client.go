@@ -0,0 +1,181 @@
+package classroom
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dkeriazisStuy/FallacyQuest/content"
+	"github.com/dkeriazisStuy/FallacyQuest/events"
+	"net"
+	"sync"
+	"time"
+)
+
+// sendQueue is how many events a client holds for the host before it drops
+// new ones.
+const sendQueue = 256
+
+// Client is a student's connection to the host. The game polls it for new
+// rounds and the leaderboard, and it passes the student's events on as a
+// Sink. Rounds are checked against the student's content; one that can't be
+// played with it ends the connection.
+type Client struct {
+	ID        string
+	c         *conn
+	cat       *content.Catalog
+	questions []content.Question
+	// out holds events until the client's own goroutine sends them, so
+	// the game never waits on the network.
+	out  chan Message
+	stop chan struct{}
+	once sync.Once
+	mu   sync.Mutex
+	err  error
+	// rounds counts the rounds received, so a poller can tell a new one.
+	rounds      int
+	round       *Round
+	leaderboard []Entry
+}
+
+// Dial joins the host at addr as name, to play rounds from cat and
+// questions.
+func Dial(addr, name string, cat *content.Catalog, questions []content.Question) (*Client, error) {
+	nc, err := net.DialTimeout("tcp", addr, helloTimeout)
+	if err != nil {
+		return nil, err
+	}
+	c := newConn(nc)
+	if err := c.send(Message{Type: "hello", Version: Version, Name: name}); err != nil {
+		nc.Close()
+		return nil, err
+	}
+	m, err := c.receive(time.Now().Add(helloTimeout))
+	switch {
+	case err != nil:
+	case m.Type == "error":
+		err = errors.New("classroom: " + m.Error)
+	case m.Type != "welcome":
+		err = errors.New("classroom: expected welcome, got " + m.Type)
+	case m.Round != nil:
+		err = checkRound(m.Round, cat, questions)
+	}
+	if err != nil {
+		nc.Close()
+		return nil, err
+	}
+	cl := &Client{ID: m.ID, c: c, cat: cat, questions: questions, out: make(chan Message, sendQueue), stop: make(chan struct{})}
+	if m.Round != nil {
+		cl.rounds, cl.round = 1, m.Round
+	}
+	go cl.read()
+	go cl.write()
+	return cl, nil
+}
+
+func (cl *Client) read() {
+	for {
+		m, err := cl.c.receive(time.Time{})
+		cl.mu.Lock()
+		switch {
+		case err != nil:
+			cl.err = err
+		case m.Type == "round" && m.Round != nil:
+			if cl.err = checkRound(m.Round, cl.cat, cl.questions); cl.err == nil {
+				cl.rounds += 1
+				cl.round = m.Round
+			}
+		case m.Type == "leaderboard":
+			cl.leaderboard = m.Leaderboard
+		case m.Type == "error":
+			cl.err = errors.New("classroom: " + m.Error)
+		}
+		err = cl.err
+		cl.mu.Unlock()
+		if err != nil {
+			cl.c.c.Close()
+			return
+		}
+	}
+}
+
+func (cl *Client) write() {
+	for {
+		select {
+		case m := <-cl.out:
+			if !cl.send(m) {
+				return
+			}
+		case <-cl.stop:
+			// Send what was queued before leaving.
+			for {
+				select {
+				case m := <-cl.out:
+					if !cl.send(m) {
+						return
+					}
+				default:
+					cl.c.c.Close()
+					return
+				}
+			}
+		}
+	}
+}
+
+// send writes m to the host, closing the connection if it can't.
+func (cl *Client) send(m Message) bool {
+	err := cl.c.send(m)
+	if err != nil {
+		cl.mu.Lock()
+		if cl.err == nil {
+			cl.err = err
+		}
+		cl.mu.Unlock()
+		cl.c.c.Close()
+	}
+	return err == nil
+}
+
+func checkRound(r *Round, cat *content.Catalog, questions []content.Question) error {
+	if err := r.Check(cat, questions); err != nil {
+		return fmt.Errorf("classroom: the host sent a round this game can't play: %v", err)
+	}
+	return nil
+}
+
+// Round returns the latest round and how many rounds have come so far.
+func (cl *Client) Round() (*Round, int) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	return cl.round, cl.rounds
+}
+
+// Leaderboard is the latest leaderboard from the host.
+func (cl *Client) Leaderboard() []Entry {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	return cl.leaderboard
+}
+
+// Err is why the connection was lost, or nil while it holds.
+func (cl *Client) Err() error {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	return cl.err
+}
+
+// Handle queues one of the student's events for the host without waiting.
+// An event that finds the queue full is dropped. A failed send closes the
+// connection, which Err then reports.
+func (cl *Client) Handle(r events.Record) {
+	select {
+	case cl.out <- Message{Type: "event", Event: &r}:
+	default:
+	}
+}
+
+// Close leaves the class once the events already queued are sent. It
+// doesn't wait for them.
+func (cl *Client) Close() error {
+	cl.once.Do(func() { close(cl.stop) })
+	return nil
+}
@@ -0,0 +1,40 @@
+// Package classroom plays one round of questions across a class on the
+// local network. A host picks the questions and sends the same round to
+// every client; the students answer on their own machines and their events
+// flow back to the host, which keeps the leaderboard.
+//
+// # Protocol
+//
+// Clients connect to the host over TCP. Each side sends JSON messages, one
+// per line, each an object whose "type" says what it is:
+//
+//	{"type": "hello", "version": 1, "name": "Ann"}
+//
+// The rest of the fields depend on the type:
+//
+//	hello        client → host, first: version, name
+//	welcome      host → client, in reply: version, id, and round if one
+//	             is being played
+//	error        host → client: error; the host then hangs up
+//	round        host → client: round, a new round to play at once
+//	event        client → host: event, one of the client's session events
+//	             as written by package events
+//	leaderboard  host → client: leaderboard, every player by score
+//
+// A round is a seed, the scoring settings and the questions in order, each
+// with the choices to offer:
+//
+//	{"seed": 7, "scoring": {...}, "questions": [
+//		{"question": "65c6701dbec063fb", "fallacy": "hominem",
+//		 "choices": ["hominem", "straw", "emotion", "cum"]}]}
+//
+// Questions are named by their ID in the question bank, so host and clients
+// must have the same content. A client hangs up on a round it can't play,
+// such as one with an unknown scorer, questions missing from its bank or
+// fallacies missing from its catalog.
+//
+// The version is Version. A host turns away a hello with any other version
+// with an error message, so both ends must be upgraded together; fields
+// added without changing the meaning of the old ones don't need a new
+// version, since unknown fields are ignored.
+package classroom
@@ -0,0 +1,167 @@
+package classroom
+
+import (
+	"fmt"
+	"github.com/dkeriazisStuy/FallacyQuest/dashboard"
+	"github.com/dkeriazisStuy/FallacyQuest/events"
+	"net"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Host runs a class: it takes clients, sends them rounds and follows their
+// events on a dashboard board, which the leaderboard is drawn from.
+type Host struct {
+	board   *dashboard.Board
+	ln      net.Listener
+	mu      sync.Mutex
+	clients map[string]*conn
+	round   *Round
+	nextID  int
+	closed  bool
+}
+
+// Listen starts a host on addr, following the class on board.
+func Listen(addr string, board *dashboard.Board) (*Host, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	h := &Host{board: board, ln: ln, clients: make(map[string]*conn)}
+	go h.accept()
+	return h, nil
+}
+
+// Addr is the address the host listens on.
+func (h *Host) Addr() net.Addr {
+	return h.ln.Addr()
+}
+
+// Close hangs up on everyone and stops taking clients.
+func (h *Host) Close() error {
+	h.mu.Lock()
+	h.closed = true
+	for _, c := range h.clients {
+		c.c.Close()
+	}
+	h.mu.Unlock()
+	return h.ln.Close()
+}
+
+func (h *Host) accept() {
+	for {
+		c, err := h.ln.Accept()
+		if err != nil {
+			return
+		}
+		go h.serve(newConn(c))
+	}
+}
+
+// serve greets a client, then follows its events until it hangs up.
+func (h *Host) serve(c *conn) {
+	defer c.c.Close()
+	hello, err := c.receive(time.Now().Add(helloTimeout))
+	if err != nil {
+		return
+	}
+	switch {
+	case hello.Type != "hello":
+		c.send(Message{Type: "error", Error: fmt.Sprintf("expected hello, got %q", hello.Type)})
+		return
+	case hello.Version != Version:
+		c.send(Message{Type: "error", Error: fmt.Sprintf("host speaks protocol version %d, client %d", Version, hello.Version)})
+		return
+	}
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return
+	}
+	h.nextID += 1
+	id := fmt.Sprintf("client%d", h.nextID)
+	h.clients[id] = c
+	round := h.round
+	h.mu.Unlock()
+	h.board.Join(id, hello.Name, time.Now())
+	if err := c.send(Message{Type: "welcome", Version: Version, ID: id, Round: round}); err == nil {
+		h.broadcastLeaderboard()
+		for {
+			m, err := c.receive(time.Time{})
+			if err != nil {
+				break
+			}
+			if m.Type != "event" || m.Event == nil {
+				continue
+			}
+			h.board.Feed(id, *m.Event)
+			switch m.Event.Event.(type) {
+			case events.Answered, events.SessionStart, events.SessionEnd:
+				h.broadcastLeaderboard()
+			}
+		}
+	}
+	h.mu.Lock()
+	delete(h.clients, id)
+	h.mu.Unlock()
+	h.board.Disconnect(id)
+	h.broadcastLeaderboard()
+}
+
+// Start sends everyone a new round, and anyone who joins later gets it too.
+func (h *Host) Start(r *Round) {
+	h.mu.Lock()
+	h.round = r
+	h.mu.Unlock()
+	h.broadcast(Message{Type: "round", Round: r})
+}
+
+// Clients is the number of clients connected.
+func (h *Host) Clients() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.clients)
+}
+
+// Leaderboard ranks the players who have joined by score.
+func (h *Host) Leaderboard() []Entry {
+	var entries []Entry
+	for _, p := range h.board.Snapshot().Players {
+		if p.Client == dashboard.Local {
+			continue
+		}
+		entries = append(entries, Entry{
+			Name:      p.Name,
+			Score:     p.Score,
+			Answered:  p.Answered,
+			Correct:   p.Correct,
+			Playing:   p.Playing,
+			Connected: p.Connected,
+		})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Score > entries[j].Score
+	})
+	return entries
+}
+
+func (h *Host) broadcastLeaderboard() {
+	h.broadcast(Message{Type: "leaderboard", Leaderboard: h.Leaderboard()})
+}
+
+func (h *Host) broadcast(m Message) {
+	h.mu.Lock()
+	clients := make([]*conn, 0, len(h.clients))
+	for _, c := range h.clients {
+		clients = append(clients, c)
+	}
+	h.mu.Unlock()
+	for _, c := range clients {
+		// A client too slow to take it is dropped; its reader then sees
+		// the closed connection and cleans up.
+		if err := c.send(m); err != nil {
+			c.c.Close()
+		}
+	}
+}
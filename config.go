@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/dkeriazisStuy/FallacyQuest/quest"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// config is read from config.json in the config directory. Missing fields
+// keep their defaults.
+type config struct {
+	Scoring quest.ScoringConfig `json:"scoring"`
+	// RevealMissed outlines the answer phrases a wrong check left out.
+	RevealMissed bool `json:"revealMissed"`
+	// Adaptive picks normal rounds to suit the player's rating. It is off
+	// with -seed, so everyone sharing a seed gets the same questions.
+	Adaptive bool `json:"adaptive"`
+}
+
+var settings = defaultConfig()
+
+func defaultConfig() config {
+	return config{
+		Scoring: quest.DefaultScoring(),
+	}
+}
+
+// configDir is where settings and saves live.
+func configDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "fallacyquest"), nil
+}
+
+// loadConfig reads path, or config.json in the config directory when path is
+// empty. Only an explicitly named file has to exist.
+func loadConfig(path string) error {
+	explicit := path != ""
+	if !explicit {
+		dir, err := configDir()
+		if err != nil {
+			return nil
+		}
+		path = filepath.Join(dir, "config.json")
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	if _, err := settings.Scoring.NewScorer(); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package content
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+)
+
+// CatalogVersion is the catalog schema this package understands.
+const CatalogVersion = 1
+
+// Categories lists the fallacy families a catalog entry may belong to.
+var Categories = []string{"relevance", "causal", "formal", "ambiguity"}
+
+// Fallacy is one entry of the catalog.
+type Fallacy struct {
+	Key         string   `json:"key"`
+	Name        string   `json:"name"`
+	Args        int      `json:"args"`
+	Roles       []string `json:"roles"`
+	Category    string   `json:"category"`
+	Description string   `json:"description"`
+	Related     []string `json:"related"`
+	Line        int      `json:"-"`
+}
+
+// Catalog is the versioned list of every fallacy the game knows about.
+type Catalog struct {
+	Version   int
+	File      string
+	Fallacies []*Fallacy
+	byKey     map[string]*Fallacy
+}
+
+// Get returns the fallacy with the given key, or nil.
+func (c *Catalog) Get(key string) *Fallacy {
+	return c.byKey[key]
+}
+
+// Keys returns every fallacy key in catalog order.
+func (c *Catalog) Keys() []string {
+	keys := make([]string, len(c.Fallacies))
+	for i, f := range c.Fallacies {
+		keys[i] = f.Key
+	}
+	return keys
+}
+
+// LoadCatalog reads catalog.json from the top of fsys.
+func LoadCatalog(fsys fs.FS) (*Catalog, error) {
+	const name = "catalog.json"
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	c := &Catalog{File: name, byKey: make(map[string]*Fallacy)}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, ErrorList{{name, 1, "expected a JSON object"}}
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, ErrorList{{name, lineAt(data, errOffset(err, int(dec.InputOffset()))), err.Error()}}
+		}
+		switch tok {
+		case "version":
+			err = dec.Decode(&c.Version)
+		case "fallacies":
+			err = decodeList(dec, data, func(line int) error {
+				f := &Fallacy{Line: line}
+				if err := dec.Decode(f); err != nil {
+					return err
+				}
+				c.Fallacies = append(c.Fallacies, f)
+				return nil
+			})
+		default:
+			err = fmt.Errorf("unknown field %q", tok)
+		}
+		if err != nil {
+			return nil, ErrorList{{name, lineAt(data, errOffset(err, int(dec.InputOffset()))), err.Error()}}
+		}
+	}
+	var errs ErrorList
+	if c.Version != CatalogVersion {
+		errs = append(errs, &Error{name, 1, fmt.Sprintf("unsupported catalog version %d, want %d", c.Version, CatalogVersion)})
+	}
+	for _, f := range c.Fallacies {
+		switch {
+		case f.Key == "":
+			errs = append(errs, &Error{name, f.Line, "fallacy has no key"})
+		case c.byKey[f.Key] != nil:
+			errs = append(errs, &Error{name, f.Line, fmt.Sprintf("duplicate key %q (first on line %d)", f.Key, c.byKey[f.Key].Line)})
+		default:
+			c.byKey[f.Key] = f
+		}
+		if f.Args < 1 {
+			errs = append(errs, &Error{name, f.Line, fmt.Sprintf("%q must take at least one answer", f.Key)})
+		}
+	}
+	if len(errs) > 0 {
+		return c, errs
+	}
+	return c, nil
+}
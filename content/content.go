@@ -0,0 +1,239 @@
+// Package content loads the fallacy catalog and question bank from JSON files.
+package content
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+//go:embed catalog.json questions/*.json
+var builtin embed.FS
+
+// Difficulty levels a question can be given.
+const (
+	Easy   = 1
+	Medium = 2
+	Hard   = 3
+)
+
+// Question is a single argument the player has to pick apart. Explanation
+// says why it is the named fallacy, and Notes[i], if given, explains the
+// phrase at Ans[i]. Difficulty is optional; see Level.
+type Question struct {
+	Name        string   `json:"name"`
+	Phrases     []string `json:"phrases"`
+	Ans         []int    `json:"ans"`
+	Difficulty  int      `json:"difficulty,omitempty"`
+	Explanation string   `json:"explanation,omitempty"`
+	Notes       []string `json:"notes,omitempty"`
+	File        string   `json:"-"`
+	Line        int      `json:"-"`
+}
+
+// Level is the question's difficulty from Easy to Hard. Questions without
+// one are rated by how many phrases there are to pick and to pick from.
+func (q *Question) Level() int {
+	if q.Difficulty != 0 {
+		return q.Difficulty
+	}
+	level := Easy + len(q.Ans) - 1
+	if len(q.Phrases) > 8 {
+		level += 1
+	}
+	if level > Hard {
+		level = Hard
+	}
+	return level
+}
+
+// ID identifies the question by its fallacy and text, so it stays the same
+// when questions are reordered or moved between files.
+func (q *Question) ID() string {
+	sum := sha1.Sum([]byte(q.Name + "\x00" + strings.Join(q.Phrases, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Error describes a problem at a position in a content file.
+type Error struct {
+	File string
+	Line int
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Msg)
+}
+
+// ErrorList collects every problem found while loading.
+type ErrorList []*Error
+
+func (l ErrorList) Error() string {
+	msgs := make([]string, len(l))
+	for i, e := range l {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Builtin returns the catalog and question files compiled into the binary.
+func Builtin() fs.FS {
+	return builtin
+}
+
+// Load reads every .json file in the questions directory of fsys and checks
+// each question against cat.
+func Load(fsys fs.FS, cat *Catalog) ([]Question, error) {
+	names, err := fs.Glob(fsys, "questions/*.json")
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, errors.New("content: no question files found")
+	}
+	sort.Strings(names)
+	var questions []Question
+	var errs ErrorList
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		qs, fileErrs := decodeFile(name, data)
+		errs = append(errs, fileErrs...)
+		for _, q := range qs {
+			errs = append(errs, q.check(cat)...)
+		}
+		questions = append(questions, qs...)
+	}
+	if len(errs) > 0 {
+		return questions, errs
+	}
+	return questions, nil
+}
+
+func (q *Question) check(cat *Catalog) ErrorList {
+	var errs ErrorList
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, &Error{q.File, q.Line, fmt.Sprintf(format, args...)})
+	}
+	if len(q.Phrases) == 0 {
+		fail("question has no phrases")
+	}
+	for i, p := range q.Phrases {
+		if strings.TrimPrefix(p, "\n") == "" {
+			fail("phrase %d is empty", i)
+		}
+	}
+	for _, a := range q.Ans {
+		if a < 0 || a >= len(q.Phrases) {
+			fail("ans index %d outside phrases (0-%d)", a, len(q.Phrases)-1)
+		}
+	}
+	if q.Difficulty != 0 && (q.Difficulty < Easy || q.Difficulty > Hard) {
+		fail("difficulty %d outside %d-%d", q.Difficulty, Easy, Hard)
+	}
+	if len(q.Notes) > 0 && len(q.Notes) != len(q.Ans) {
+		fail("has %d notes for %d answers", len(q.Notes), len(q.Ans))
+	}
+	if f := cat.Get(q.Name); f == nil {
+		fail("unknown fallacy name %q", q.Name)
+	} else if len(q.Ans) != f.Args {
+		fail("%q takes %d answers, got %d", q.Name, f.Args, len(q.Ans))
+	}
+	return errs
+}
+
+func decodeFile(name string, data []byte) ([]Question, ErrorList) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		return nil, ErrorList{{name, 1, "expected a JSON array of questions"}}
+	}
+	var questions []Question
+	err := decodeRest(dec, data, func(line int) error {
+		q := Question{File: name, Line: line}
+		if err := dec.Decode(&q); err != nil {
+			return err
+		}
+		questions = append(questions, q)
+		return nil
+	})
+	if err != nil {
+		return questions, ErrorList{{name, lineAt(data, errOffset(err, int(dec.InputOffset()))), err.Error()}}
+	}
+	return questions, nil
+}
+
+// decodeList walks the JSON array at the decoder's position, calling elem
+// with the line each element starts on. elem decodes the element itself.
+func decodeList(dec *json.Decoder, data []byte, elem func(line int) error) error {
+	if tok, err := dec.Token(); err != nil {
+		return err
+	} else if tok != json.Delim('[') {
+		return fmt.Errorf("expected a JSON array, got %v", tok)
+	}
+	return decodeRest(dec, data, elem)
+}
+
+func decodeRest(dec *json.Decoder, data []byte, elem func(line int) error) error {
+	for dec.More() {
+		start := skipSeparators(data, int(dec.InputOffset()))
+		if err := elem(lineAt(data, start)); err != nil {
+			return &offsetError{err, start}
+		}
+	}
+	_, err := dec.Token()
+	return err
+}
+
+type offsetError struct {
+	err    error
+	offset int
+}
+
+func (e *offsetError) Error() string { return e.err.Error() }
+func (e *offsetError) Unwrap() error { return e.err }
+
+func errOffset(err error, fallback int) int {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var offErr *offsetError
+	if errors.As(err, &offErr) {
+		fallback = offErr.offset
+	}
+	switch {
+	case errors.As(err, &syntaxErr):
+		return int(syntaxErr.Offset)
+	case errors.As(err, &typeErr) && offErr != nil:
+		// Decode reports type errors from the start of the element.
+		return offErr.offset + int(typeErr.Offset)
+	}
+	return fallback
+}
+
+func skipSeparators(data []byte, off int) int {
+	for off < len(data) {
+		switch data[off] {
+		case ' ', '\t', '\r', '\n', ',':
+			off++
+		default:
+			return off
+		}
+	}
+	return off
+}
+
+func lineAt(data []byte, off int) int {
+	if off > len(data) {
+		off = len(data)
+	}
+	return bytes.Count(data[:off], []byte("\n")) + 1
+}
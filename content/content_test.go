@@ -0,0 +1,129 @@
+package content
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+const testCatalog = `{
+  "version": 1,
+  "fallacies": [
+    {"key": "hominem", "name": "Ad Hominem", "args": 1, "roles": ["the attack"], "category": "relevance"},
+    {"key": "straw", "name": "Straw Man", "args": 2, "roles": ["the argument", "the distortion"], "category": "relevance"}
+  ]
+}`
+
+func load(t *testing.T, questions string) ([]Question, error) {
+	t.Helper()
+	fsys := fstest.MapFS{
+		"catalog.json":     {Data: []byte(testCatalog)},
+		"questions/a.json": {Data: []byte(questions)},
+	}
+	cat, err := LoadCatalog(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Load(fsys, cat)
+}
+
+func TestLoad(t *testing.T) {
+	qs, err := load(t, `[
+  {"name": "hominem", "phrases": ["a", "b"], "ans": [1]},
+
+  {"name": "straw", "phrases": ["a", "b", "c"], "ans": [0, 2]}
+]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qs) != 2 {
+		t.Fatalf("got %d questions, want 2", len(qs))
+	}
+	for i, line := range []int{2, 4} {
+		if qs[i].File != "questions/a.json" || qs[i].Line != line {
+			t.Errorf("question %d at %s:%d, want questions/a.json:%d", i, qs[i].File, qs[i].Line, line)
+		}
+	}
+}
+
+func TestLoadErrorLines(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		data string
+		line int
+	}{
+		{"type", `[
+  {"name": "hominem", "phrases": ["a", "b"], "ans": [1]},
+  {"name": "hominem", "phrases": ["a", "b"], "ans": [1]},
+
+  {"name": 3}
+]`, 5},
+		{"type in a later field", `[
+  {"name": "hominem", "phrases": ["a", "b"], "ans": [1]},
+  {
+    "name": "hominem",
+    "phrases": ["a", "b"],
+    "ans": "1"
+  }
+]`, 6},
+		{"syntax", `[
+  {"name": "hominem", "phrases": ["a", "b"], "ans": [1]},
+  {"name" "hominem"}
+]`, 3},
+		{"unknown field", `[
+  {"name": "hominem", "phrases": ["a", "b"], "ans": [1]},
+  {"name": "hominem", "phrases": ["a", "b"], "ans": [1], "extra": true}
+]`, 3},
+		{"check", `[
+  {"name": "hominem", "phrases": ["a", "b"], "ans": [1]},
+  {"name": "straw", "phrases": ["a", "b"], "ans": [1]}
+]`, 3},
+		{"unknown fallacy", `[
+
+  {"name": "nope", "phrases": ["a"], "ans": [0]}
+]`, 3},
+	} {
+		_, err := load(t, tt.data)
+		var errs ErrorList
+		if !errors.As(err, &errs) || len(errs) != 1 {
+			t.Errorf("%s: got %v, want one error", tt.name, err)
+			continue
+		}
+		if errs[0].File != "questions/a.json" || errs[0].Line != tt.line {
+			t.Errorf("%s: error at %s:%d, want questions/a.json:%d: %v", tt.name, errs[0].File, errs[0].Line, tt.line, errs[0])
+		}
+	}
+}
+
+func TestLoadCatalogErrorLines(t *testing.T) {
+	cat, err := LoadCatalog(fstest.MapFS{"catalog.json": {Data: []byte(`{
+  "version": 1,
+  "fallacies": [
+    {"key": "hominem", "name": "Ad Hominem", "args": 1},
+    {"key": "hominem", "name": "Again", "args": 1},
+    {"key": "straw", "name": "Straw Man", "args": "2"}
+  ]
+}`)}})
+	var errs ErrorList
+	if cat != nil || !errors.As(err, &errs) || len(errs) != 1 || errs[0].Line != 6 {
+		t.Errorf("got %v, want a type error on line 6", err)
+	}
+}
+
+func TestBuiltinLint(t *testing.T) {
+	cat, err := LoadCatalog(Builtin())
+	if err != nil {
+		t.Fatal(err)
+	}
+	questions, err := Load(Builtin(), cat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := Lint(cat, questions); len(errs) > 0 {
+		t.Errorf("the built-in content has lint errors:\n%v", errs)
+	}
+	cat.Fallacies[0].Category = "induction"
+	if errs := Lint(cat, questions); len(errs) != 1 {
+		t.Errorf("a category outside %v got %d errors, want 1", Categories, len(errs))
+	}
+}
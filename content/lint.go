@@ -0,0 +1,79 @@
+package content
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Lint reports every inconsistency in cat and questions that Load and
+// LoadCatalog let through.
+func Lint(cat *Catalog, questions []Question) ErrorList {
+	var errs ErrorList
+	categories := make(map[string]bool)
+	for _, c := range Categories {
+		categories[c] = true
+	}
+	names := make(map[string]*Fallacy)
+	for _, f := range cat.Fallacies {
+		fail := func(format string, args ...interface{}) {
+			errs = append(errs, &Error{cat.File, f.Line, fmt.Sprintf("%s: ", f.Key) + fmt.Sprintf(format, args...)})
+		}
+		if f.Name == "" {
+			fail("missing name")
+		} else if other := names[strings.ToLower(f.Name)]; other != nil {
+			fail("name %q already used by %q", f.Name, other.Key)
+		} else {
+			names[strings.ToLower(f.Name)] = f
+		}
+		if len(f.Roles) != f.Args {
+			fail("takes %d answers but has %d roles", f.Args, len(f.Roles))
+		}
+		for i, r := range f.Roles {
+			if r == "" {
+				fail("role %d is empty", i)
+			}
+		}
+		if !categories[f.Category] {
+			fail("unknown category %q (want one of %s)", f.Category, strings.Join(Categories, ", "))
+		}
+		if f.Description == "" {
+			fail("missing description")
+		}
+		seen := make(map[string]bool)
+		for _, r := range f.Related {
+			switch {
+			case r == f.Key:
+				fail("lists itself as related")
+			case cat.Get(r) == nil:
+				fail("related fallacy %q is not in the catalog", r)
+			case seen[r]:
+				fail("related fallacy %q listed twice", r)
+			}
+			seen[r] = true
+		}
+		if len(f.Related) < 2 {
+			fail("needs at least 2 related fallacies for answer choices, has %d", len(f.Related))
+		}
+	}
+	first := make(map[string]*Question)
+	for i := range questions {
+		q := &questions[i]
+		fail := func(format string, args ...interface{}) {
+			errs = append(errs, &Error{q.File, q.Line, fmt.Sprintf(format, args...)})
+		}
+		seen := make(map[int]bool)
+		for _, a := range q.Ans {
+			if seen[a] {
+				fail("ans index %d listed twice", a)
+			}
+			seen[a] = true
+		}
+		key := strings.ToLower(strings.Join(q.Phrases, " "))
+		if other := first[key]; other != nil {
+			fail("duplicate of question at %s:%d", other.File, other.Line)
+		} else {
+			first[key] = q
+		}
+	}
+	return errs
+}
@@ -0,0 +1,242 @@
+// Package dashboard serves a live view of a class over HTTP: who is
+// playing, what they are answering and how well, built from their session
+// events. It needs nothing beyond the local network.
+package dashboard
+
+import (
+	"embed"
+	"encoding/json"
+	"github.com/dkeriazisStuy/FallacyQuest/content"
+	"github.com/dkeriazisStuy/FallacyQuest/events"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:embed index.html
+var static embed.FS
+
+// Local is the client name of the game the dashboard runs in.
+const Local = "local"
+
+// Board follows the events of every client. It is safe for concurrent use,
+// so the game can feed it while the server reads it.
+type Board struct {
+	cat     *content.Catalog
+	text    map[string]string
+	mu      sync.Mutex
+	clients map[string]*Player
+}
+
+// Player is what the board knows about one client. Score, Combo, Answered
+// and Correct are for the session being played, or the last one; Fallacies
+// counts every session since the client joined.
+type Player struct {
+	Client    string               `json:"client"`
+	Name      string               `json:"name"`
+	Mode      string               `json:"mode"`
+	Playing   bool                 `json:"playing"`
+	Connected bool                 `json:"connected"`
+	LastSeen  time.Time            `json:"lastSeen"`
+	Question  *Question            `json:"question,omitempty"`
+	Score     float64              `json:"score"`
+	Combo     int                  `json:"combo"`
+	Answered  int                  `json:"answered"`
+	Correct   int                  `json:"correct"`
+	Fallacies map[string]*Accuracy `json:"fallacies"`
+}
+
+// Question is the question a player has in front of them.
+type Question struct {
+	Number  int    `json:"number"`
+	Fallacy string `json:"fallacy"`
+	Name    string `json:"name"`
+	Text    string `json:"text"`
+	Checks  int    `json:"checks"`
+}
+
+// Accuracy counts the questions answered about one fallacy.
+type Accuracy struct {
+	Key     string `json:"key"`
+	Name    string `json:"name"`
+	Seen    int    `json:"seen"`
+	Correct int    `json:"correct"`
+}
+
+// NewBoard follows players answering questions from cat.
+func NewBoard(cat *content.Catalog, questions []content.Question) *Board {
+	b := &Board{cat: cat, text: make(map[string]string), clients: make(map[string]*Player)}
+	for _, q := range questions {
+		b.text[q.ID()] = strings.Join(strings.Fields(strings.Join(q.Phrases, " ")), " ")
+	}
+	return b
+}
+
+// name is a fallacy's display name.
+func (b *Board) name(key string) string {
+	if f := b.cat.Get(key); f != nil {
+		return f.Name
+	}
+	return key
+}
+
+// Handle follows the local game.
+func (b *Board) Handle(r events.Record) {
+	b.Feed(Local, r)
+}
+
+// Feed updates client's player from one of their events.
+func (b *Board) Feed(client string, r events.Record) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	p := b.player(client)
+	p.Connected = true
+	p.LastSeen = r.Time
+	switch e := r.Event.(type) {
+	case events.SessionStart:
+		// Players without a profile keep the name they joined with.
+		if e.Player != "" {
+			p.Name = e.Player
+		}
+		p.Mode = e.Mode
+		p.Playing = true
+		p.Question = nil
+		p.Score = 0
+		p.Combo = 0
+		p.Answered = 0
+		p.Correct = 0
+	case events.QuestionShown:
+		p.Question = &Question{Number: e.Number, Fallacy: e.Fallacy, Name: b.name(e.Fallacy), Text: b.text[e.Question]}
+	case events.Checked:
+		if p.Question != nil {
+			p.Question.Checks += 1
+		}
+		if !e.Correct {
+			p.Combo = 0
+		}
+		p.Score = e.Score
+	case events.Answered:
+		p.Score = e.Score
+		p.Answered += 1
+		a := p.Fallacies[e.Fallacy]
+		if a == nil {
+			a = &Accuracy{Key: e.Fallacy, Name: b.name(e.Fallacy)}
+			p.Fallacies[e.Fallacy] = a
+		}
+		a.Seen += 1
+		if e.Correct {
+			p.Combo += 1
+			p.Correct += 1
+			a.Correct += 1
+		} else {
+			p.Combo = 0
+		}
+	case events.SessionEnd:
+		p.Score = e.Score
+		p.Playing = false
+		p.Question = nil
+	}
+}
+
+func (b *Board) player(client string) *Player {
+	p := b.clients[client]
+	if p == nil {
+		p = &Player{Client: client, Fallacies: make(map[string]*Accuracy)}
+		b.clients[client] = p
+	}
+	return p
+}
+
+// Join shows client on the board under name before they start playing.
+func (b *Board) Join(client, name string, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	p := b.player(client)
+	p.Name = name
+	p.Connected = true
+	p.LastSeen = now
+}
+
+// Disconnect marks client as gone, keeping what they did.
+func (b *Board) Disconnect(client string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if p := b.clients[client]; p != nil {
+		p.Connected = false
+		p.Playing = false
+		p.Question = nil
+	}
+}
+
+// Snapshot is the whole board at one moment: every player by client name,
+// and the class's accuracy on each fallacy in catalog order.
+type Snapshot struct {
+	Players   []Player   `json:"players"`
+	Fallacies []Accuracy `json:"fallacies"`
+}
+
+// Snapshot copies the board.
+func (b *Board) Snapshot() Snapshot {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var s Snapshot
+	class := make(map[string]*Accuracy)
+	for _, p := range b.clients {
+		c := *p
+		c.Fallacies = make(map[string]*Accuracy)
+		for key, a := range p.Fallacies {
+			copied := *a
+			c.Fallacies[key] = &copied
+			total := class[key]
+			if total == nil {
+				total = &Accuracy{Key: key, Name: a.Name}
+				class[key] = total
+			}
+			total.Seen += a.Seen
+			total.Correct += a.Correct
+		}
+		if p.Question != nil {
+			q := *p.Question
+			c.Question = &q
+		}
+		s.Players = append(s.Players, c)
+	}
+	sort.Slice(s.Players, func(i, j int) bool {
+		return s.Players[i].Client < s.Players[j].Client
+	})
+	for _, f := range b.cat.Fallacies {
+		a := Accuracy{Key: f.Key, Name: f.Name}
+		if total := class[f.Key]; total != nil {
+			a = *total
+		}
+		s.Fallacies = append(s.Fallacies, a)
+	}
+	return s
+}
+
+// Handler serves the dashboard page at / and the board as JSON at
+// /api/board.
+func (b *Board) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/board", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		json.NewEncoder(w).Encode(b.Snapshot())
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		page, err := static.ReadFile("index.html")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(page)
+	})
+	return mux
+}
@@ -0,0 +1,165 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"github.com/dkeriazisStuy/FallacyQuest/content"
+	"github.com/dkeriazisStuy/FallacyQuest/events"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestBoard(t *testing.T) (*Board, []content.Question) {
+	t.Helper()
+	cat, err := content.LoadCatalog(content.Builtin())
+	if err != nil {
+		t.Fatal(err)
+	}
+	questions, err := content.Load(content.Builtin(), cat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewBoard(cat, questions), questions
+}
+
+// feed gives client's events to b, a second apart.
+func feed(b *Board, client string, evs ...events.Event) {
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	for i, e := range evs {
+		b.Feed(client, events.Record{Seq: i + 1, Time: start.Add(time.Duration(i) * time.Second), Event: e})
+	}
+}
+
+func getBoard(t *testing.T, url string) Snapshot {
+	t.Helper()
+	resp, err := http.Get(url + "/api/board")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/json" {
+		t.Fatalf("got %s, %q", resp.Status, resp.Header.Get("Content-Type"))
+	}
+	var s Snapshot
+	if err := json.NewDecoder(resp.Body).Decode(&s); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestBoard(t *testing.T) {
+	b, questions := newTestBoard(t)
+	q1, q2 := questions[0], questions[1]
+	feed(b, Local,
+		events.SessionStart{Mode: "normal", Player: "Ann", Length: 10},
+		events.QuestionShown{Number: 1, Question: q1.ID(), Fallacy: q1.Name},
+		events.Checked{Question: q1.ID(), Fallacy: q1.Name, Correct: true, Credit: 1},
+		events.Answered{Question: q1.ID(), Fallacy: q1.Name, Correct: true, Checks: 1, Points: 10, Score: 10},
+		events.QuestionShown{Number: 2, Question: q2.ID(), Fallacy: q2.Name},
+		events.Checked{Question: q2.ID(), Fallacy: q2.Name, Score: 9},
+	)
+	feed(b, "client1",
+		events.SessionStart{Mode: "classroom", Player: "Bob"},
+		events.QuestionShown{Number: 1, Question: q2.ID(), Fallacy: q2.Name},
+		events.Answered{Question: q2.ID(), Fallacy: q2.Name, Score: 0},
+		events.SessionEnd{Score: 0, Questions: 1, Finished: true},
+	)
+	srv := httptest.NewServer(b.Handler())
+	defer srv.Close()
+
+	s := getBoard(t, srv.URL)
+	if len(s.Players) != 2 || s.Players[0].Client != "client1" || s.Players[1].Client != Local {
+		t.Fatalf("got players %+v", s.Players)
+	}
+	ann, bob := s.Players[1], s.Players[0]
+	if ann.Name != "Ann" || !ann.Playing || ann.Score != 9 || ann.Combo != 0 || ann.Answered != 1 || ann.Correct != 1 {
+		t.Errorf("Ann: %+v", ann)
+	}
+	text := strings.Join(strings.Fields(strings.Join(q2.Phrases, " ")), " ")
+	if ann.Question == nil || ann.Question.Number != 2 || ann.Question.Fallacy != q2.Name || ann.Question.Text != text || ann.Question.Checks != 1 {
+		t.Errorf("Ann's question: %+v", ann.Question)
+	}
+	if bob.Name != "Bob" || bob.Playing || bob.Question != nil || bob.Answered != 1 || bob.Correct != 0 {
+		t.Errorf("Bob: %+v", bob)
+	}
+	if a := ann.Fallacies[q1.Name]; a == nil || a.Seen != 1 || a.Correct != 1 {
+		t.Errorf("Ann on %s: %+v", q1.Name, a)
+	}
+	// The class totals cover every fallacy in the catalog, in order.
+	if len(s.Fallacies) != len(b.cat.Fallacies) {
+		t.Fatalf("got %d fallacies, want %d", len(s.Fallacies), len(b.cat.Fallacies))
+	}
+	for i, a := range s.Fallacies {
+		want := Accuracy{Key: b.cat.Fallacies[i].Key, Name: b.cat.Fallacies[i].Name}
+		if a.Key == q1.Name {
+			want.Seen += 1
+			want.Correct += 1
+		}
+		if a.Key == q2.Name {
+			want.Seen += 1
+		}
+		if a != want {
+			t.Errorf("class on %s: %+v, want %+v", a.Key, a, want)
+		}
+	}
+
+	// A new session starts the counts over, but not the fallacies.
+	feed(b, Local, events.SessionStart{Mode: "normal", Player: "Ann"})
+	ann = getBoard(t, srv.URL).Players[1]
+	if ann.Score != 0 || ann.Answered != 0 || ann.Correct != 0 || ann.Question != nil {
+		t.Errorf("Ann in a new session: %+v", ann)
+	}
+	if a := ann.Fallacies[q1.Name]; a == nil || a.Seen != 1 {
+		t.Errorf("Ann on %s in a new session: %+v", q1.Name, a)
+	}
+
+	b.Disconnect("client1")
+	if bob := getBoard(t, srv.URL).Players[0]; bob.Connected || bob.Answered != 1 {
+		t.Errorf("Bob after leaving: %+v", bob)
+	}
+}
+
+func TestBoardRaw(t *testing.T) {
+	b, _ := newTestBoard(t)
+	b.Join("client1", "Cy", time.Now())
+	rec := httptest.NewRecorder()
+	b.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/api/board", nil))
+	var raw struct {
+		Players []map[string]interface{} `json:"players"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &raw); err != nil {
+		t.Fatal(err)
+	}
+	if len(raw.Players) != 1 || raw.Players[0]["name"] != "Cy" || raw.Players[0]["connected"] != true {
+		t.Errorf("got %s", rec.Body)
+	}
+	if _, ok := raw.Players[0]["question"]; ok {
+		t.Errorf("a player with no question has one: %s", rec.Body)
+	}
+}
+
+func TestPage(t *testing.T) {
+	b, _ := newTestBoard(t)
+	srv := httptest.NewServer(b.Handler())
+	defer srv.Close()
+	resp, err := http.Get(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") || !strings.Contains(string(page), "/api/board") {
+		t.Errorf("got %s, %q, %d bytes", resp.Status, resp.Header.Get("Content-Type"), len(page))
+	}
+	resp, err = http.Get(srv.URL + "/nope")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("got %s for an unknown page", resp.Status)
+	}
+}
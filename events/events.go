@@ -0,0 +1,224 @@
+// Package events carries a typed record of everything that happens in a
+// session to whoever wants it: save files, analytics, log files.
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// An Event is something that happened in a session.
+type Event interface {
+	Kind() string
+}
+
+// SessionStart begins a session. Length is its number of questions, or 0
+// when it runs until time or lives run out.
+type SessionStart struct {
+	Mode      string  `json:"mode"`
+	Player    string  `json:"player,omitempty"`
+	Seed      int64   `json:"seed"`
+	Length    int     `json:"length,omitempty"`
+	TimeLimit float64 `json:"timeLimit,omitempty"`
+	Lives     int     `json:"lives,omitempty"`
+}
+
+// QuestionShown puts a new question in front of the player. Question is its
+// ID and Fallacy its answer.
+type QuestionShown struct {
+	Number   int      `json:"number"`
+	Question string   `json:"question"`
+	Fallacy  string   `json:"fallacy"`
+	Choices  []string `json:"choices"`
+}
+
+// PhraseToggled selects or deselects a phrase.
+type PhraseToggled struct {
+	Phrase   int  `json:"phrase"`
+	Selected bool `json:"selected"`
+}
+
+// ChoiceSelected picks one of the offered fallacies.
+type ChoiceSelected struct {
+	Choice  int    `json:"choice"`
+	Fallacy string `json:"fallacy"`
+}
+
+// Checked grades an answer. Chosen is the fallacy picked, empty if none
+// was, and Selected the phrases. Time is the seconds into the question and
+// Elapsed the seconds into the session. Score is the session's score once
+// any penalty for the check is taken.
+type Checked struct {
+	Question string  `json:"question"`
+	Fallacy  string  `json:"fallacy"`
+	Chosen   string  `json:"chosen"`
+	Selected []int   `json:"selected"`
+	Correct  bool    `json:"correct"`
+	Credit   float64 `json:"credit"`
+	Time     float64 `json:"time"`
+	Elapsed  float64 `json:"elapsed"`
+	Score    float64 `json:"score"`
+}
+
+// Skipped moves on from a question.
+type Skipped struct {
+	Question string `json:"question"`
+	Correct  bool   `json:"correct"`
+}
+
+// Answered is the outcome of a question the player moved on from, whether
+// by skipping or because the session ended. Score is the session's score
+// with the points banked and any penalty for skipping taken.
+type Answered struct {
+	Question string  `json:"question"`
+	Fallacy  string  `json:"fallacy"`
+	Correct  bool    `json:"correct"`
+	Checks   int     `json:"checks"`
+	Credit   float64 `json:"credit"`
+	Points   float64 `json:"points"`
+	Time     float64 `json:"time"`
+	Score    float64 `json:"score"`
+}
+
+// SessionEnd closes a session, Finished unless it was abandoned.
+type SessionEnd struct {
+	Score     float64 `json:"score"`
+	Questions int     `json:"questions"`
+	Elapsed   float64 `json:"elapsed"`
+	Finished  bool    `json:"finished"`
+}
+
+func (SessionStart) Kind() string   { return "sessionStart" }
+func (QuestionShown) Kind() string  { return "questionShown" }
+func (PhraseToggled) Kind() string  { return "phraseToggled" }
+func (ChoiceSelected) Kind() string { return "choiceSelected" }
+func (Checked) Kind() string        { return "checked" }
+func (Skipped) Kind() string        { return "skipped" }
+func (Answered) Kind() string       { return "answered" }
+func (SessionEnd) Kind() string     { return "sessionEnd" }
+
+// decoders decode the data of each kind of event.
+var decoders = map[string]func(data []byte) (Event, error){
+	"sessionStart": func(data []byte) (Event, error) {
+		var e SessionStart
+		err := json.Unmarshal(data, &e)
+		return e, err
+	},
+	"questionShown": func(data []byte) (Event, error) {
+		var e QuestionShown
+		err := json.Unmarshal(data, &e)
+		return e, err
+	},
+	"phraseToggled": func(data []byte) (Event, error) {
+		var e PhraseToggled
+		err := json.Unmarshal(data, &e)
+		return e, err
+	},
+	"choiceSelected": func(data []byte) (Event, error) {
+		var e ChoiceSelected
+		err := json.Unmarshal(data, &e)
+		return e, err
+	},
+	"checked": func(data []byte) (Event, error) {
+		var e Checked
+		err := json.Unmarshal(data, &e)
+		return e, err
+	},
+	"skipped": func(data []byte) (Event, error) {
+		var e Skipped
+		err := json.Unmarshal(data, &e)
+		return e, err
+	},
+	"answered": func(data []byte) (Event, error) {
+		var e Answered
+		err := json.Unmarshal(data, &e)
+		return e, err
+	},
+	"sessionEnd": func(data []byte) (Event, error) {
+		var e SessionEnd
+		err := json.Unmarshal(data, &e)
+		return e, err
+	},
+}
+
+// Record is an event as it was emitted: numbered from 1 and timestamped.
+type Record struct {
+	Seq   int
+	Time  time.Time
+	Event Event
+}
+
+type envelope struct {
+	Seq  int             `json:"seq"`
+	Time time.Time       `json:"time"`
+	Kind string          `json:"kind"`
+	Data json.RawMessage `json:"data"`
+}
+
+func (r Record) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(r.Event)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(envelope{r.Seq, r.Time, r.Event.Kind(), data})
+}
+
+func (r *Record) UnmarshalJSON(data []byte) error {
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return err
+	}
+	decode := decoders[env.Kind]
+	if decode == nil {
+		return fmt.Errorf("events: unknown kind %q", env.Kind)
+	}
+	e, err := decode(env.Data)
+	if err != nil {
+		return fmt.Errorf("events: %s: %v", env.Kind, err)
+	}
+	*r = Record{env.Seq, env.Time, e}
+	return nil
+}
+
+// A Sink receives every record emitted on a bus.
+type Sink interface {
+	Handle(r Record)
+}
+
+// SinkFunc lets a function be a Sink.
+type SinkFunc func(r Record)
+
+func (f SinkFunc) Handle(r Record) {
+	f(r)
+}
+
+// Bus hands each emitted event to its sinks in the order they subscribed.
+// A nil Bus drops everything, so emitting is always safe.
+type Bus struct {
+	// Now timestamps records; nil means time.Now.
+	Now   func() time.Time
+	sinks []Sink
+	seq   int
+}
+
+// Subscribe adds s to the sinks.
+func (b *Bus) Subscribe(s Sink) {
+	b.sinks = append(b.sinks, s)
+}
+
+// Emit numbers, timestamps and delivers e.
+func (b *Bus) Emit(e Event) {
+	if b == nil {
+		return
+	}
+	now := time.Now
+	if b.Now != nil {
+		now = b.Now
+	}
+	b.seq += 1
+	r := Record{Seq: b.seq, Time: now(), Event: e}
+	for _, s := range b.sinks {
+		s.Handle(r)
+	}
+}
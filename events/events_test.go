@@ -0,0 +1,139 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// session is one of every kind of event, in the order a session has them.
+var session = []Event{
+	SessionStart{Mode: "normal", Player: "Ann", Seed: 7, Length: 10},
+	QuestionShown{Number: 1, Question: "ab12", Fallacy: "straw", Choices: []string{"straw", "post"}},
+	ChoiceSelected{Choice: 0, Fallacy: "straw"},
+	PhraseToggled{Phrase: 2, Selected: true},
+	Checked{Question: "ab12", Fallacy: "straw", Chosen: "straw", Selected: []int{2}, Correct: true, Credit: 1, Time: 3.5, Elapsed: 3.5, Score: 0},
+	Skipped{Question: "ab12", Correct: true},
+	Answered{Question: "ab12", Fallacy: "straw", Correct: true, Checks: 1, Credit: 1, Points: 10, Time: 3.5, Score: 10},
+	SessionEnd{Score: 10, Questions: 1, Elapsed: 4, Finished: true},
+}
+
+// clock ticks a second each time it is read.
+func clock() func() time.Time {
+	t := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		t = t.Add(time.Second)
+		return t
+	}
+}
+
+// decode reads the records in a JSON Lines log.
+func decode(t *testing.T, data []byte) []Record {
+	t.Helper()
+	var recs []Record
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		var r Record
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			t.Fatalf("%s: %v", sc.Bytes(), err)
+		}
+		recs = append(recs, r)
+	}
+	return recs
+}
+
+func TestJSONL(t *testing.T) {
+	var buf bytes.Buffer
+	var sent []Record
+	bus := Bus{Now: clock()}
+	j := NewJSONL(&buf)
+	bus.Subscribe(j)
+	bus.Subscribe(SinkFunc(func(r Record) { sent = append(sent, r) }))
+	for i, e := range session {
+		bus.Emit(e)
+		// Nothing is written until the session ends.
+		if _, end := e.(SessionEnd); !end && buf.Len() > 0 {
+			t.Fatalf("wrote %d bytes after event %d", buf.Len(), i+1)
+		}
+	}
+	if err := j.Err(); err != nil {
+		t.Fatal(err)
+	}
+	got := decode(t, buf.Bytes())
+	if !reflect.DeepEqual(got, sent) {
+		t.Errorf("read back\n%+v\nwant\n%+v", got, sent)
+	}
+	for i, r := range got {
+		if r.Seq != i+1 {
+			t.Errorf("record %d numbered %d", i+1, r.Seq)
+		}
+	}
+}
+
+func TestOpenJSONL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	// Each open appends to the log.
+	for i := 0; i < 2; i++ {
+		j, err := OpenJSONL(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bus := Bus{Now: clock()}
+		bus.Subscribe(j)
+		bus.Emit(session[0])
+		if err := j.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recs := decode(t, data); len(recs) != 2 || !reflect.DeepEqual(recs[1].Event, session[0]) {
+		t.Errorf("read back %+v", recs)
+	}
+}
+
+type failWriter struct{ writes int }
+
+func (w *failWriter) Write(p []byte) (int, error) {
+	w.writes += 1
+	return 0, errors.New("disk full")
+}
+
+func TestJSONLError(t *testing.T) {
+	w := &failWriter{}
+	j := NewJSONL(w)
+	for i := 0; i < 3; i++ {
+		for _, e := range session {
+			j.Handle(Record{Seq: 1, Event: e})
+		}
+	}
+	if j.Err() == nil || j.Close() == nil {
+		t.Error("lost the write error")
+	}
+	if w.writes != 1 {
+		t.Errorf("kept writing after an error: %d writes", w.writes)
+	}
+}
+
+func TestUnmarshalUnknown(t *testing.T) {
+	var r Record
+	if err := json.Unmarshal([]byte(`{"seq": 1, "kind": "teleported", "data": {}}`), &r); err == nil {
+		t.Error("decoded an unknown kind")
+	}
+	if err := json.Unmarshal([]byte(`{"seq": 1, "kind": "checked", "data": {"correct": "yes"}}`), &r); err == nil {
+		t.Error("decoded a checked event with a bad field")
+	}
+}
+
+func TestNilBus(t *testing.T) {
+	var bus *Bus
+	bus.Emit(session[0])
+}
@@ -0,0 +1,64 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+)
+
+// JSONL is a Sink writing one JSON record per line. The first write error
+// is kept and stops further writes.
+type JSONL struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+	c   io.Closer
+	err error
+}
+
+// NewJSONL writes records to w.
+func NewJSONL(w io.Writer) *JSONL {
+	bw := bufio.NewWriter(w)
+	return &JSONL{w: bw, enc: json.NewEncoder(bw)}
+}
+
+// OpenJSONL appends records to the file at path, creating it if need be.
+func OpenJSONL(path string) (*JSONL, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	j := NewJSONL(f)
+	j.c = f
+	return j, nil
+}
+
+// Handle writes r. Records are flushed at the end of each session, so a log
+// is whole up to the last session even if the game crashes.
+func (j *JSONL) Handle(r Record) {
+	if j.err != nil {
+		return
+	}
+	j.err = j.enc.Encode(r)
+	if _, end := r.Event.(SessionEnd); end && j.err == nil {
+		j.err = j.w.Flush()
+	}
+}
+
+// Err returns the first write error.
+func (j *JSONL) Err() error {
+	return j.err
+}
+
+// Close flushes the records and closes the file, if JSONL opened it.
+func (j *JSONL) Close() error {
+	if j.err == nil {
+		j.err = j.w.Flush()
+	}
+	if j.c != nil {
+		if err := j.c.Close(); j.err == nil {
+			j.err = err
+		}
+	}
+	return j.err
+}
@@ -0,0 +1,111 @@
+// Package highscore keeps the local leaderboards, one per game mode and
+// question count.
+package highscore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/dkeriazisStuy/FallacyQuest/storage"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Version is the file schema written by this package.
+const Version = 1
+
+// Size is how many entries each board keeps.
+const Size = 10
+
+// Entry is one score on a board.
+type Entry struct {
+	Initials string    `json:"initials"`
+	Player   string    `json:"player,omitempty"`
+	Score    float64   `json:"score"`
+	Date     time.Time `json:"date"`
+}
+
+// Table holds every board, keyed by Key.
+type Table struct {
+	Version int                `json:"version"`
+	Boards  map[string][]Entry `json:"boards"`
+}
+
+// Key names the board for a mode played to the given length: a number of
+// questions, or of seconds for timed modes.
+func Key(mode string, length int) string {
+	return fmt.Sprintf("%s/%d", mode, length)
+}
+
+// Rank returns the 1-based place score would take on board key, or 0 if it
+// would not make the board.
+func (t *Table) Rank(key string, score float64) int {
+	board := t.Boards[key]
+	rank := sort.Search(len(board), func(i int) bool {
+		return board[i].Score < score
+	})
+	if rank >= Size {
+		return 0
+	}
+	return rank + 1
+}
+
+// Add puts e on board key and returns its rank, or 0 if it did not make it.
+func (t *Table) Add(key string, e Entry) int {
+	rank := t.Rank(key, e.Score)
+	if rank == 0 {
+		return 0
+	}
+	if t.Boards == nil {
+		t.Boards = make(map[string][]Entry)
+	}
+	e.Initials = strings.ToUpper(e.Initials)
+	board := append(t.Boards[key], Entry{})
+	copy(board[rank:], board[rank-1:])
+	board[rank-1] = e
+	if len(board) > Size {
+		board = board[:Size]
+	}
+	t.Boards[key] = board
+	return rank
+}
+
+// Load reads the table at path. A missing file is an empty table.
+func Load(path string) (*Table, error) {
+	t := &Table{Version: Version, Boards: make(map[string][]Entry)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return t, nil
+	} else if err != nil {
+		return t, err
+	}
+	if err := json.Unmarshal(data, t); err != nil {
+		return &Table{Version: Version, Boards: make(map[string][]Entry)}, fmt.Errorf("%s: %v", path, err)
+	}
+	if t.Version > Version {
+		return t, fmt.Errorf("%s: saved by a newer version of the game (schema %d, want %d)", path, t.Version, Version)
+	}
+	if t.Boards == nil {
+		t.Boards = make(map[string][]Entry)
+	}
+	for key := range t.Boards {
+		board := t.Boards[key]
+		sort.SliceStable(board, func(i, j int) bool {
+			return board[i].Score > board[j].Score
+		})
+	}
+	return t, nil
+}
+
+// Save writes the table to path, replacing the old file atomically.
+func (t *Table) Save(path string) error {
+	t.Version = Version
+	data, err := json.MarshalIndent(t, "", "\t")
+	if err != nil {
+		return err
+	}
+	return storage.WriteFile(path, data, 0644)
+}
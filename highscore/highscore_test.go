@@ -0,0 +1,108 @@
+package highscore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func scores(board []Entry) []float64 {
+	var s []float64
+	for _, e := range board {
+		s = append(s, e.Score)
+	}
+	return s
+}
+
+func TestAdd(t *testing.T) {
+	var table Table
+	key := Key("normal", 10)
+	if key != "normal/10" {
+		t.Errorf("Key got %q", key)
+	}
+	for i, tt := range []struct {
+		score float64
+		rank  int
+	}{
+		{50, 1},
+		{70, 1},
+		{60, 2},
+		{50, 4}, // ties go after the scores already there
+		{10, 5},
+	} {
+		if rank := table.Add(key, Entry{Initials: "abc", Score: tt.score}); rank != tt.rank {
+			t.Errorf("score %d (%g): rank %d, want %d", i, tt.score, rank, tt.rank)
+		}
+	}
+	if got := scores(table.Boards[key]); len(got) != 5 || got[0] != 70 || got[4] != 10 {
+		t.Errorf("board %v", got)
+	}
+	if e := table.Boards[key][0]; e.Initials != "ABC" {
+		t.Errorf("initials %q, want them upper case", e.Initials)
+	}
+	if len(table.Boards) != 1 || table.Rank(Key("normal", 20), 1) != 1 {
+		t.Error("boards for other lengths aren't kept apart")
+	}
+}
+
+func TestAddFull(t *testing.T) {
+	var table Table
+	for i := 1; i <= Size; i++ {
+		table.Add("k", Entry{Score: float64(i * 10)})
+	}
+	if rank := table.Add("k", Entry{Score: 10}); rank != 0 {
+		t.Errorf("a tie with the last place got rank %d on a full board", rank)
+	}
+	if rank := table.Add("k", Entry{Score: 15}); rank != Size {
+		t.Errorf("got rank %d, want %d", rank, Size)
+	}
+	if rank := table.Add("k", Entry{Score: 1000}); rank != 1 {
+		t.Errorf("got rank %d, want 1", rank)
+	}
+	board := table.Boards["k"]
+	if len(board) != Size || board[0].Score != 1000 || board[Size-1].Score != 20 {
+		t.Errorf("board %v", scores(board))
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	table, err := Load(filepath.Join(dir, "missing.json"))
+	if err != nil || table.Boards == nil {
+		t.Fatalf("a missing file: %+v, %v", table, err)
+	}
+
+	// Boards are put back in order however they were saved.
+	path := filepath.Join(dir, "scores.json")
+	data := `{"version": 1, "boards": {"k": [{"score": 1}, {"score": 3}, {"score": 2}]}}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	table, err = Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := scores(table.Boards["k"]); len(got) != 3 || got[0] != 3 || got[2] != 1 {
+		t.Errorf("board %v", got)
+	}
+	table.Add("k", Entry{Initials: "z", Score: 5})
+	if err := table.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	table, err = Load(path)
+	if err != nil || len(table.Boards["k"]) != 4 || table.Boards["k"][0].Initials != "Z" {
+		t.Errorf("after saving: %+v, %v", table, err)
+	}
+
+	for name, data := range map[string]string{
+		"newer":  `{"version": 2, "boards": {}}`,
+		"broken": `{"version":`,
+	} {
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if table, err := Load(path); err == nil || table == nil {
+			t.Errorf("%s: got %+v, %v", name, table, err)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Package input maps the mouse, keyboard and gamepads onto game actions, so
+// the game never reads devices directly and tests can feed it fake input.
+package input
+
+import (
+	"github.com/faiface/pixel"
+)
+
+// Action is something the player can do, whatever device they use.
+type Action int
+
+const (
+	// Click is the primary pointer button.
+	Click Action = iota
+	// Next and Prev move the focus.
+	Next
+	Prev
+	// Toggle selects or deselects what has focus.
+	Toggle
+	// Confirm checks an answer or presses the focused button.
+	Confirm
+	// Skip moves on to the next question.
+	Skip
+	// Back leaves the current screen.
+	Back
+	// Pick1 to Pick9 choose an option directly. Pick1+Action(i) is the
+	// i'th option counting from zero.
+	Pick1
+	Pick2
+	Pick3
+	Pick4
+	Pick5
+	Pick6
+	Pick7
+	Pick8
+	Pick9
+	// Erase deletes the last character of text being typed.
+	Erase
+)
+
+// Set is a set of actions.
+type Set uint32
+
+// Has reports whether a is in the set.
+func (s Set) Has(a Action) bool {
+	return s&(1<<uint(a)) != 0
+}
+
+// With returns the set with a added.
+func (s Set) With(a Action) Set {
+	return s | 1<<uint(a)
+}
+
+// Frame is the input for one frame. Down holds the actions being held,
+// JustDown those that started (or auto-repeated) this frame and JustUp
+// those let go this frame. Text is what was typed this frame.
+type Frame struct {
+	Pointer  pixel.Vec `json:"pointer"`
+	Down     Set       `json:"down,omitempty"`
+	JustDown Set       `json:"justDown,omitempty"`
+	JustUp   Set       `json:"justUp,omitempty"`
+	Text     string    `json:"text,omitempty"`
+}
+
+// JustPressed reports whether a started this frame.
+func (f Frame) JustPressed(a Action) bool {
+	return f.JustDown.Has(a)
+}
+
+// Pressed reports whether a is held.
+func (f Frame) Pressed(a Action) bool {
+	return f.Down.Has(a)
+}
+
+// JustReleased reports whether a was let go this frame.
+func (f Frame) JustReleased(a Action) bool {
+	return f.JustUp.Has(a)
+}
+
+// A Source produces one Frame of input each time it is polled.
+type Source interface {
+	Poll() Frame
+}
+
+// Mock is a Source that returns Frames in order, then empty frames with the
+// pointer where it was last.
+type Mock struct {
+	Frames []Frame
+	next   int
+	last   pixel.Vec
+}
+
+func (m *Mock) Poll() Frame {
+	if m.next >= len(m.Frames) {
+		return Frame{Pointer: m.last}
+	}
+	f := m.Frames[m.next]
+	m.next += 1
+	m.last = f.Pointer
+	return f
+}
@@ -0,0 +1,98 @@
+package input
+
+import (
+	"github.com/faiface/pixel/pixelgl"
+)
+
+// stickDeadZone is how far a stick must be pushed to count as a direction.
+const stickDeadZone = .5
+
+// binding is what triggers one action on each device. Repeat lets a held
+// key fire again at the system's key repeat rate.
+type binding struct {
+	action  Action
+	keys    []pixelgl.Button
+	buttons []pixelgl.GamepadButton
+	repeat  bool
+}
+
+var bindings = []binding{
+	{Click, []pixelgl.Button{pixelgl.MouseButtonLeft}, nil, false},
+	{Next, []pixelgl.Button{pixelgl.KeyRight, pixelgl.KeyDown}, []pixelgl.GamepadButton{pixelgl.ButtonDpadRight, pixelgl.ButtonDpadDown, pixelgl.ButtonRightBumper}, true},
+	{Prev, []pixelgl.Button{pixelgl.KeyLeft, pixelgl.KeyUp}, []pixelgl.GamepadButton{pixelgl.ButtonDpadLeft, pixelgl.ButtonDpadUp, pixelgl.ButtonLeftBumper}, true},
+	{Toggle, []pixelgl.Button{pixelgl.KeySpace}, []pixelgl.GamepadButton{pixelgl.ButtonA}, false},
+	{Confirm, []pixelgl.Button{pixelgl.KeyEnter, pixelgl.KeyKPEnter}, []pixelgl.GamepadButton{pixelgl.ButtonX, pixelgl.ButtonStart}, false},
+	{Skip, []pixelgl.Button{pixelgl.KeyS}, []pixelgl.GamepadButton{pixelgl.ButtonY}, false},
+	{Back, []pixelgl.Button{pixelgl.KeyEscape}, []pixelgl.GamepadButton{pixelgl.ButtonB, pixelgl.ButtonBack}, false},
+	{Pick1, []pixelgl.Button{pixelgl.Key1}, nil, false},
+	{Pick2, []pixelgl.Button{pixelgl.Key2}, nil, false},
+	{Pick3, []pixelgl.Button{pixelgl.Key3}, nil, false},
+	{Pick4, []pixelgl.Button{pixelgl.Key4}, nil, false},
+	{Pick5, []pixelgl.Button{pixelgl.Key5}, nil, false},
+	{Pick6, []pixelgl.Button{pixelgl.Key6}, nil, false},
+	{Pick7, []pixelgl.Button{pixelgl.Key7}, nil, false},
+	{Pick8, []pixelgl.Button{pixelgl.Key8}, nil, false},
+	{Pick9, []pixelgl.Button{pixelgl.Key9}, nil, false},
+	{Erase, []pixelgl.Button{pixelgl.KeyBackspace}, nil, true},
+}
+
+// Window reads the mouse and keyboard of a window and every connected
+// gamepad. Tab and Shift+Tab also move the focus, as does the left stick.
+type Window struct {
+	Win   *pixelgl.Window
+	stick Set
+}
+
+// NewWindow returns the Source for win.
+func NewWindow(win *pixelgl.Window) *Window {
+	return &Window{Win: win}
+}
+
+func (w *Window) Poll() Frame {
+	f := Frame{Pointer: w.Win.MousePosition(), Text: w.Win.Typed()}
+	press := func(a Action, down, justDown, justUp bool) {
+		if down {
+			f.Down = f.Down.With(a)
+		}
+		if justDown {
+			f.JustDown = f.JustDown.With(a)
+		}
+		if justUp {
+			f.JustUp = f.JustUp.With(a)
+		}
+	}
+	for _, b := range bindings {
+		for _, k := range b.keys {
+			press(b.action, w.Win.Pressed(k), w.Win.JustPressed(k) || b.repeat && w.Win.Repeated(k), w.Win.JustReleased(k))
+		}
+	}
+	tab := Next
+	if w.Win.Pressed(pixelgl.KeyLeftShift) || w.Win.Pressed(pixelgl.KeyRightShift) {
+		tab = Prev
+	}
+	press(tab, w.Win.Pressed(pixelgl.KeyTab), w.Win.JustPressed(pixelgl.KeyTab) || w.Win.Repeated(pixelgl.KeyTab), w.Win.JustReleased(pixelgl.KeyTab))
+	var stick Set
+	for js := pixelgl.Joystick1; js <= pixelgl.JoystickLast; js++ {
+		if !w.Win.JoystickPresent(js) {
+			continue
+		}
+		for _, b := range bindings {
+			for _, gb := range b.buttons {
+				press(b.action, w.Win.JoystickPressed(js, gb), w.Win.JoystickJustPressed(js, gb), w.Win.JoystickJustReleased(js, gb))
+			}
+		}
+		// The stick's y axis points down, so down and right both mean Next.
+		x, y := w.Win.JoystickAxis(js, pixelgl.AxisLeftX), w.Win.JoystickAxis(js, pixelgl.AxisLeftY)
+		if x > stickDeadZone || y > stickDeadZone {
+			stick = stick.With(Next)
+		}
+		if x < -stickDeadZone || y < -stickDeadZone {
+			stick = stick.With(Prev)
+		}
+	}
+	f.Down |= stick
+	f.JustDown |= stick &^ w.stick
+	f.JustUp |= w.stick &^ stick
+	w.stick = stick
+	return f
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dkeriazisStuy/FallacyQuest/content"
+	"os"
+)
+
+// Fallacies the tutorial talks about by key.
+var tutorialKeys = []string{"analogy", "accident"}
+
+func lint(dir string) int {
+	err := loadContent(dir)
+	var problems content.ErrorList
+	if err != nil && !errors.As(err, &problems) || catalog == nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	for _, k := range tutorialKeys {
+		if catalog.Get(k) == nil {
+			problems = append(problems, &content.Error{File: catalog.File, Line: 1, Msg: fmt.Sprintf("tutorial needs fallacy %q", k)})
+		}
+	}
+	problems = append(problems, content.Lint(catalog, questions)...)
+	for _, p := range problems {
+		fmt.Println(p)
+	}
+	if len(problems) > 0 {
+		fmt.Fprintf(os.Stderr, "%d problems found\n", len(problems))
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "%d fallacies, %d questions: ok\n", len(catalog.Fallacies), len(questions))
+	return 0
+}
@@ -1,7 +1,17 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"github.com/dkeriazisStuy/FallacyQuest/classroom"
+	"github.com/dkeriazisStuy/FallacyQuest/content"
+	"github.com/dkeriazisStuy/FallacyQuest/dashboard"
+	"github.com/dkeriazisStuy/FallacyQuest/events"
+	"github.com/dkeriazisStuy/FallacyQuest/highscore"
+	"github.com/dkeriazisStuy/FallacyQuest/input"
+	"github.com/dkeriazisStuy/FallacyQuest/quest"
+	"github.com/dkeriazisStuy/FallacyQuest/replay"
 	"github.com/faiface/pixel"
 	"github.com/faiface/pixel/imdraw"
 	"github.com/faiface/pixel/pixelgl"
@@ -10,8 +20,12 @@ import (
 	"golang.org/x/image/font/basicfont"
 	"image/color"
 	_ "image/png"
+	"io/fs"
 	"math"
 	"math/rand"
+	"net/http"
+	"os"
+	"strings"
 	"time"
 )
 
@@ -33,77 +47,23 @@ var cfg = pixelgl.WindowConfig{
 	Resizable: true,
 }
 
+var atlas = text.NewAtlas(basicfont.Face7x13, text.ASCII)
+
 var winX = origX
 var winY = origY
 
-type fallacyProps struct {
-	fullName string
-	argCount int
-	related  []string
-}
-
-var fallacyNames = map[string]fallacyProps{
-	"hominem":       {"Argumentum ad Hominem", 1, []string{"straw", "emotion"}},
-	"straw":         {"Straw Man", 2, []string{"hominem", "emotion"}},
-	"emotion":       {"Appeal to Emotion", 1, []string{"hominem", "straw"}},
-	"analogy":       {"Weak Analogy", 2, []string{"slippery", "accident", "authority", "popularity", "hasty"}},
-	"hasty":         {"Hasty Generalization", 2, []string{"accident", "analogy", "authority", "popularity"}},
-	"accident":      {"Accident", 2, []string{"hasty", "analogy", "authority", "popularity"}},
-	"post":          {"Post Hoc Ergo Propter Hoc", 2, []string{"cum", "slippery", "popularity", "authority"}},
-	"cum":           {"Cum Hoc Ergo Propter Hoc", 2, []string{"post", "slippery", "popularity", "authority"}},
-	"slippery":      {"Slippery Slope", 2, []string{"cum", "post", "accident", "authority", "popularity"}},
-	"authority":     {"Fallacious Appeal to Authority", 1, []string{"popularity", "cum", "post", "accident"}},
-	"popularity":    {"Fallacious Appeal to Popularity", 1, []string{"authority", "cum", "post", "accident"}},
-	"affirming":     {"Affirming the Consequent", 2, []string{"denying", "undistributed", "composition", "division"}},
-	"denying":       {"Denying the Antecedent", 2, []string{"affirming", "undistributed", "composition", "division"}},
-	"undistributed": {"Undistributed Middle", 2, []string{"denying", "affirming", "composition", "division"}},
-	"equivocation":  {"Equivocation", 1, []string{"composition", "division"}},
-	"composition":   {"Composition", 3, []string{"division", "equivocation"}},
-	"division":      {"Division", 3, []string{"composition", "equivocation"}},
-}
-
-var fallacies = []fallacy{
-	{name: "straw", phrases: []string{"Jane complains about", "the way I clean.", "She must want", "to be able", "to eat off the floor"}, ans: []int{1, 4}},
-	{name: "hominem", phrases: []string{"Don't listen", "to Al Gore.", "He spews", "liberal propaganda."}, ans: []int{3}},
-	{name: "hominem", phrases: []string{"Rush Limbaugh", "is a pompous windbag.", "Don't listen", "to him."}, ans: []int{1}},
-	{name: "emotion", phrases: []string{"All guns", "need to be banned.", "Won't anyone", "think of the children?"}, ans: []int{3}},
-	{name: "hominem", phrases: []string{"People", "who don't believe", "in gay marriage", "are absolute sickos,", "and shouldn't be", "taken seriously"}, ans: []int{3}},
-	{name: "straw", phrases: []string{"How could", "global warming", "exist,", "it snowed", "just yesterday?"}, ans: []int{1, 3}},
-	{name: "straw", phrases: []string{"Curbing violence", "in movies", "doesn't make sense.", "Do you think", "they should just make", "movies for kids?"}, ans: []int{0, 5}},
-	{name: "straw", phrases: []string{"A cruise", "would be nice", "but we can't", "spend all our money", "on vacations!"}, ans: []int{0, 3}},
-	{name: "straw", phrases: []string{"Why do you", "want more shoes?", "Nobody needs", "a thousand pairs of shoes!"}, ans: []int{1, 3}},
-	{name: "slippery", phrases: []string{"Once", "I eat this", "chocolate,", "I will keep eating", "and won't stop."}, ans: []int{1, 3}},
-	{name: "authority", phrases: []string{"The prayer", "cured", "her rheumatism.", "She said", "it did", "and who would know better than she?"}, ans: []int{5}},
-	{name: "hasty", phrases: []string{"If they", "messed up your order", "you should", "stop doing business", "with them."}, ans: []int{1, 3}},
-	{name: "cum", phrases: []string{"Countries that don't eat meat", "have", "less prostate cancer.", "Therefore,", "eating meat", "leads to", "prostate cancer"}, ans: []int{4, 6}},
-	{name: "accident", phrases: []string{"I saw", "a teacher with their phone", "even though", "school policy", "says", "no phones in school.", "What gives?"}, ans: []int{1, 5}},
-	{name: "cum", phrases: []string{"Smokers tend", "to come from", "low income", "areas.", "What is it", "about low income", "that", "makes people smoke?"}, ans: []int{5, 7}},
-	{name: "hasty", phrases: []string{"American air is so polluted.", "I saw", "this one place", "in Houston", "with so much pollution."}, ans: []int{0, 2}},
-	{name: "post", phrases: []string{"After my granddad", "had his", "heart attack", "his hair turned", "completely white.", "I didn't know", "a heart attack", "could cause that."}, ans: []int{2, 4}},
-	{name: "authority", phrases: []string{"Gay parents", "cannot raise", "babies correctly.", "Reverend Jacob", "says that."}, ans: []int{3}},
-	{name: "popularity", phrases: []string{"Being overweight", "can't be bad.", "85% of people", "are overweight,", "as a matter", "of fact."}, ans: []int{2}},
-	{name: "popularity", phrases: []string{"Yawns are", "contagious.", "Ask anyone."}, ans: []int{2}},
-	{name: "popularity", phrases: []string{"Caesar was", "a great dictator.", "After all", "everyone loved him."}, ans: []int{3}},
-	{name: "popularity", phrases: []string{"Donald Trump", "must be", "the worst president.", "I mean,", "just look", "at how many people", "hate him."}, ans: []int{5}},
-	{name: "accident", phrases: []string{"I can", "burn tires", "in my backyard", "if I want to.", "After all,", "it's a free country."}, ans: []int{1, 5}},
-	{name: "slippery", phrases: []string{"They want", "to make", "it illegal to", "hit someone with his helmet?", "What's next,", "making tackling illegal?"}, ans: []int{2, 5}},
-	{name: "authority", phrases: []string{"When I", "retake this", "stupid", "physiology course,", "I'll get", "an athlete", "to teach", "it to me.", "They're bound", "to know", "it."}, ans: []int{5}},
-	{name: "authority", phrases: []string{"Alicia", "doesn't think", "it would be", "illegal,", "and I", "trust her."}, ans: []int{0}},
-	{name: "slippery", phrases: []string{"If something", "isn't done", "soon,", "all English people", "will", "turn Muslim."}, ans: []int{3, 5}},
-	{name: "equivocation", phrases: []string{"Professor Park", "can tell you", "if you are sick.", "After all,", "he is", "a doctor."}, ans: []int{5}},
-	{name: "composition", phrases: []string{"Sodium", "is toxic", "and so is", "chlorine.", "Therefore,", "I refuse", "to eat", "salt,", "which is", "made of", "the two."}, ans: []int{0, 4, 8}},
-	{name: "affirming", phrases: []string{"Rich people", "buy a car", "like a Mercedes or Bentley.", "You have", "a Bentley", "therefore", "you must be rich."}, ans: []int{4, 6}},
-	{name: "undistributed", phrases: []string{"All hotels", "in the Southwest chain", "have elaborate lobbies.", "The Arlington", "also has a great lobby", "therefore", "it is a Southwest hotel."}, ans: []int{3, 6}},
-	{name: "division", phrases: []string{"Water", "is wet.", "Therefore,", "both hydrogen", "and oxygen", "must be wet."}, ans: []int{0, 3, 4}},
-	{name: "denying", phrases: []string{"If you", "are not", "21 or older", "you cannot drink.", "You are 21", "therefore", "you can drink."}, ans: []int{4, 6}},
-	{name: "affirming", phrases: []string{"If Sally", "is 21 or older", "she can legally drink.", "Sally can legally drink", "therefore", "she is 21 or older"}, ans: []int{3, 5}},
-	{name: "denying", phrases: []string{"If it is legal", "for Sally to drink", "then", "she is 21 or older.", "Sally cannot legally drink", "therefore", "she is under 21."}, ans: []int{4, 6}},
-	{name: "affirming", phrases: []string{"If Sally", "is 21 or older", "she can legally drink.", "Sally is not 21 or older", "therefore", "she cannot legally drink."}, ans: []int{3, 5}},
-	{name: "affirming", phrases: []string{"If you", "dropped out", "of college,", "you wouldn't", "make much", "money.", "Chris doesn't make much money,", "therefore", "he dropped out."}, ans: []int{6, 8}},
-	{name: "equivocation", phrases: []string{"Of course", "he couldn't", "see your point.", "Dude's blind."}, ans: []int{2}},
-	{name: "affirming", phrases: []string{"When James", "gets the paper", "Mr. Fields", "gives him", "a tip.", "Yesterday,", "Mr. Fields", "gave him a tip", "so he must've", "gotten the paper."}, ans: []int{7, 9}},
-	{name: "equivocation", phrases: []string{"I'll tell you", "right now", "Mr. Horace,", "no daughter", "of mine", "is going to work", "at a", "strip mall."}, ans: []int{7}},
-}
+var catalog *content.Catalog
+
+// startReplay is a recording named on the command line, played at start.
+var startReplay *replay.Recording
+
+// board follows the class for the dashboard, if one is being served.
+var board *dashboard.Board
+
+// bus carries the events of every session to the profile and any logs.
+var bus events.Bus
+
+var questions []content.Question
 
 func resized(win *pixelgl.Window) bool {
 	if win.Bounds().W() != winX || win.Bounds().H() != winY {
@@ -121,20 +81,17 @@ type textProps struct {
 	deltaY float64
 	txt    *text.Text
 	bounds pixel.Rect
-	active bool
 }
 
 type fallacy struct {
-	win     *pixelgl.Window
-	name    string
-	ans     []int
-	phrases []string
-	texts   []textProps
-	mask    []int
+	win   *pixelgl.Window
+	s     *quest.Session
+	texts []textProps
+	mask  []int
+	focus int
 }
 
 func (f *fallacy) calcTexts() {
-	atlas := text.NewAtlas(basicfont.Face7x13, text.ASCII)
 	curLine := 0.0
 	lineX := 0.0
 	totalY := 0.0
@@ -143,7 +100,7 @@ func (f *fallacy) calcTexts() {
 	space := text.New(pixel.ZV, atlas)
 	fmt.Fprint(space, " ")
 	spacing := space.Bounds().W()
-	for i, phrase := range f.phrases {
+	for i, phrase := range f.s.Question.Phrases {
 		txt := text.New(pixel.ZV, atlas)
 		if lineX > 0 {
 			lineX += spacing
@@ -185,13 +142,17 @@ func (f *fallacy) calcTexts() {
 }
 
 func (f *fallacy) draw() {
-	if resized(f.win) {
-		f.calcTexts()
-	}
 	for i, v := range f.texts {
 		mat := pixel.IM.Scaled(v.txt.Bounds().Center(), fallacyScale).Moved(f.win.Bounds().Center().Sub(v.txt.Bounds().Center()))
 		found := false
 		v.txt.Draw(f.win, mat.Moved(pixel.V(v.deltaX, v.deltaY)))
+		if i == f.focus { // Keyboard focus
+			im := imdraw.New(nil)
+			im.Color = colornames.White
+			im.Push(pixel.V(v.bounds.Min.X, v.bounds.Min.Y-winY*6/origY), pixel.V(v.bounds.Max.X, v.bounds.Min.Y-winY*6/origY))
+			im.Line(3)
+			im.Draw(f.win)
+		}
 		for _, k := range f.mask {
 			if i == k {
 				found = true
@@ -202,14 +163,24 @@ func (f *fallacy) draw() {
 			continue
 		}
 		var edgeColor color.Color
-		if v.active { // Selected
+		if f.s.Selected[i] { // Selected
 			edgeColor = colornames.Lightblue
 		}
-		if v.bounds.Contains(f.win.MousePosition()) && edgeColor == nil { // Hover
+		if v.bounds.Contains(in.Pointer) && edgeColor == nil { // Hover
 			edgeColor = colornames.Blue
-		} else if v.bounds.Contains(f.win.MousePosition()) { // Hover and Selected
+		} else if v.bounds.Contains(in.Pointer) { // Hover and Selected
 			edgeColor = colornames.Darkblue
 		}
+		switch f.s.Marks[i] {
+		case quest.Hit: // Checked and right
+			edgeColor = colornames.Limegreen
+		case quest.Wrong: // Checked and wrong
+			edgeColor = colornames.Red
+		case quest.Missed: // Answer left out
+			if settings.RevealMissed {
+				edgeColor = colornames.Gold
+			}
+		}
 		if edgeColor != nil {
 			im := imdraw.New(nil)
 			im.Color = edgeColor
@@ -220,19 +191,16 @@ func (f *fallacy) draw() {
 	}
 }
 
-func (f *fallacy) check() {
-	f.draw()
+func (f *fallacy) update() {
 	for i, v := range f.texts {
-		if v.bounds.Contains(f.win.MousePosition()) && f.win.JustPressed(pixelgl.MouseButton1) {
-			f.texts[i].active = !v.active
+		if v.bounds.Contains(in.Pointer) && in.JustPressed(input.Click) {
+			f.s.TogglePhrase(i)
 		}
 	}
 }
 
-func randFallacy(win *pixelgl.Window) fallacy {
-	result := fallacies
-	f := result[rand.Intn(len(result))]
-	f.win = win
+func newFallacy(win *pixelgl.Window, s *quest.Session) fallacy {
+	f := fallacy{win: win, s: s, focus: -1}
 	f.calcTexts()
 	return f
 }
@@ -246,21 +214,23 @@ type button struct {
 	rect           pixel.Rect
 	pressed        bool
 	justUnpressed  bool
+	focused        bool
 }
 
-func (b *button) draw() {
-	if b.justUnpressed {
-		b.justUnpressed = false
-	}
-	im := imdraw.New(nil)
-	var buttonColor color.Color
-	if b.win.JustPressed(pixelgl.MouseButtonLeft) && b.rect.Contains(b.win.MousePosition()) {
+func (b *button) update() {
+	b.justUnpressed = false
+	if in.JustPressed(input.Click) && b.rect.Contains(in.Pointer) {
 		b.pressed = true
 	}
-	if b.pressed && !b.win.Pressed(pixelgl.MouseButtonLeft) {
+	if b.pressed && !in.Pressed(input.Click) {
 		b.pressed = false
 		b.justUnpressed = true
 	}
+}
+
+func (b *button) draw() {
+	im := imdraw.New(nil)
+	var buttonColor color.Color
 	if b.pressed {
 		buttonColor = b.pressedColor
 	} else {
@@ -271,18 +241,21 @@ func (b *button) draw() {
 	im.Rectangle(0)
 	im.Draw(b.win)
 	edge := imdraw.New(nil)
-	if b.edgeColor == nil {
+	edge.Color = b.edgeColor
+	if b.focused {
+		edge.Color = colornames.White
+	}
+	if edge.Color == nil {
 		return
 	}
-	edge.Color = b.edgeColor
 	edge.Push(b.rect.Min, b.rect.Max)
 	edge.Rectangle(3)
 	edge.Draw(b.win)
 }
 
 func (b *button) check() bool {
-	b.draw()
-	return b.justUnpressed && b.rect.Contains(b.win.MousePosition()) && b.win.JustReleased(pixelgl.MouseButtonLeft)
+	b.update()
+	return b.justUnpressed && b.rect.Contains(in.Pointer) && in.JustReleased(input.Click) || b.focused && in.JustPressed(input.Confirm)
 }
 
 func newButton(win *pixelgl.Window, rect pixel.Rect, unpressedColor color.Color, pressedColor color.Color) button {
@@ -299,22 +272,24 @@ type radioButton struct {
 	display string
 	deltaY  float64
 	b       button
-	pressed bool
 }
 
 type choice struct {
-	win     *pixelgl.Window
-	centerX float64
-	centerY float64
-	buttons []radioButton
+	win           *pixelgl.Window
+	centerX       float64
+	centerY       float64
+	buttons       []radioButton
+	selected      int
+	focus         int
+	selectedColor color.Color
 }
 
 func newChoice(win *pixelgl.Window, displays []string, names []string) choice {
 	var buttons []radioButton
 	for i := range displays {
-		buttons = append(buttons, radioButton{name: names[i], display: displays[i], pressed: false})
+		buttons = append(buttons, radioButton{name: names[i], display: displays[i]})
 	}
-	c := choice{win: win, buttons: buttons}
+	c := choice{win: win, buttons: buttons, selected: -1, focus: -1}
 	c.calcChoice()
 	return c
 }
@@ -327,7 +302,7 @@ func (c *choice) setCenter(centerX float64, centerY float64) {
 
 func (c *choice) calcChoice() {
 	for i := range c.buttons {
-		deltaY := winY * radioSpace / origY * ((float64(len(c.buttons) - 1))/2 - float64(i))
+		deltaY := winY * radioSpace / origY * ((float64(len(c.buttons)-1))/2 - float64(i))
 		center := pixel.V(c.centerX, c.centerY+deltaY)
 		c.buttons[i].deltaY = deltaY
 		b := newButton(c.win, pixel.R(center.X-winX*radioSize/origX, center.Y-winY*radioSize/origY, center.X+winX*radioSize/origX, center.Y+winY*radioSize/origY), color.Transparent, color.Transparent)
@@ -335,29 +310,26 @@ func (c *choice) calcChoice() {
 	}
 }
 
-func (c *choice) draw() {
-	atlas := text.NewAtlas(basicfont.Face7x13, text.ASCII)
-	curPressed := -1
+// update returns true when a click changed the selection.
+func (c *choice) update() bool {
 	newPressed := -1
 	for i := range c.buttons {
-		if c.buttons[i].pressed {
-			curPressed = i
-		} else if c.buttons[i].b.check() {
+		if i != c.selected && c.buttons[i].b.check() {
 			newPressed = i
 		}
 	}
-	pressed := curPressed
 	if newPressed > -1 {
-		pressed = newPressed
+		c.selected = newPressed
 	}
+	return newPressed > -1
+}
+
+func (c *choice) draw() {
 	im := imdraw.New(nil)
 	for i := range c.buttons {
-		if i == pressed {
-			c.buttons[i].pressed = true
-		} else {
-			c.buttons[i].pressed = false
-		}
-		if c.buttons[i].pressed {
+		if i == c.selected && c.selectedColor != nil {
+			im.Color = c.selectedColor
+		} else if i == c.selected {
 			im.Color = colornames.Blue
 		} else if c.buttons[i].b.pressed {
 			im.Color = colornames.Lightgray
@@ -370,6 +342,11 @@ func (c *choice) draw() {
 		im.Color = colornames.Gray
 		im.Push(center)
 		im.Circle(winY*radioSize/origY, 3)
+		if i == c.focus {
+			im.Color = colornames.White
+			im.Push(center)
+			im.Circle(winY*(radioSize+5)/origY, 2)
+		}
 		txt := text.New(pixel.ZV, atlas)
 		fmt.Fprint(txt, c.buttons[i].display)
 		txt.Draw(c.win, pixel.IM.ScaledXY(txt.Bounds().Center(), pixel.V(winX*radioScale/origX, winY*radioScale/origY)).Moved(center.Add(pixel.V(txt.Bounds().W()*winX*(radioScale/2)/origX+winX*(radioSize+10)/origX, 0)).Sub(txt.Bounds().Center())))
@@ -377,447 +354,765 @@ func (c *choice) draw() {
 	im.Draw(c.win)
 }
 
-func shuffle(fallacySlice *[]string) {
-	for i := range *fallacySlice {
-		j := rand.Intn(i + 1)
-		(*fallacySlice)[i], (*fallacySlice)[j] = (*fallacySlice)[j], (*fallacySlice)[i]
-	}
+type menuScene struct {
+	st                                                      *sceneStack
+	titlePos                                                pixel.Vec
+	titleTxt, profTxt                                       *text.Text
+	startTxt, practiceTxt, studyTxt, blitzTxt, survTxt      *text.Text
+	tutTxt, quitTxt, statsTxt, replaysTxt                   *text.Text
+	start, practice, study, blitz, survival, tutorial, quit button
+	profile, stats, replays                                 button
+	focus                                                   focusList
+}
+
+func newMenuScene(st *sceneStack) *menuScene {
+	m := &menuScene{st: st}
+	m.titleTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprint(m.titleTxt, "Fallacy Quest")
+	m.startTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprint(m.startTxt, "Start")
+	m.practiceTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprint(m.practiceTxt, "Practice")
+	m.studyTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprint(m.studyTxt, "Study")
+	m.blitzTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprint(m.blitzTxt, "Blitz")
+	m.survTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprint(m.survTxt, "Survival")
+	m.tutTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprint(m.tutTxt, "Tutorial")
+	m.quitTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprint(m.quitTxt, "Quit")
+	m.profTxt = text.New(pixel.ZV, atlas)
+	m.statsTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprint(m.statsTxt, "Stats")
+	m.replaysTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprint(m.replaysTxt, "Replays")
+	return m
 }
 
-func getFallacyChoices(name string) []string {
-	result := []string{name}
-	related := fallacyNames[name].related
-	shuffle(&related)
-	result = append(result, related[0], related[1])
-	fallacyKeys := make([]string, len(fallacyNames))
-	i := 0
-	for k := range fallacyNames {
-		fallacyKeys[i] = k
-		i += 1
+func (m *menuScene) enter() {}
+
+func (m *menuScene) exit() {}
+
+func (m *menuScene) onResize() {
+	r := m.st.win.Bounds()
+	m.titlePos = pixel.V(r.W()/2, 8.5*r.H()/11)
+	m.start = newButton(m.st.win, centeredRect(pixel.V(r.W()/2, 7.4*r.H()/11), 120, 25), colornames.Sandybrown, colornames.Rosybrown)
+	m.practice = newButton(m.st.win, centeredRect(pixel.V(r.W()/2, 6.4*r.H()/11), 120, 25), colornames.Sandybrown, colornames.Rosybrown)
+	m.study = newButton(m.st.win, centeredRect(pixel.V(r.W()/2, 5.4*r.H()/11), 120, 25), colornames.Sandybrown, colornames.Rosybrown)
+	m.blitz = newButton(m.st.win, centeredRect(pixel.V(r.W()/2, 4.4*r.H()/11), 120, 25), colornames.Sandybrown, colornames.Rosybrown)
+	m.survival = newButton(m.st.win, centeredRect(pixel.V(r.W()/2, 3.4*r.H()/11), 120, 25), colornames.Sandybrown, colornames.Rosybrown)
+	m.tutorial = newButton(m.st.win, centeredRect(pixel.V(r.W()/2, 2.4*r.H()/11), 120, 25), colornames.Sandybrown, colornames.Rosybrown)
+	m.quit = newButton(m.st.win, centeredRect(pixel.V(r.W()/2, 1.4*r.H()/11), 120, 25), colornames.Sandybrown, colornames.Rosybrown)
+	m.profile = newButton(m.st.win, pixel.R(r.Max.X-winX*260/origX, r.Max.Y-winY*60/origY, r.Max.X-winX*10/origX, r.Max.Y-winY*10/origY), colornames.Sandybrown, colornames.Rosybrown)
+	m.stats = newButton(m.st.win, pixel.R(r.Max.X-winX*260/origX, r.Max.Y-winY*120/origY, r.Max.X-winX*10/origX, r.Max.Y-winY*70/origY), colornames.Sandybrown, colornames.Rosybrown)
+	m.replays = newButton(m.st.win, pixel.R(r.Max.X-winX*260/origX, r.Max.Y-winY*180/origY, r.Max.X-winX*10/origX, r.Max.Y-winY*130/origY), colornames.Sandybrown, colornames.Rosybrown)
+	m.profTxt.Clear()
+	if player != nil {
+		fmt.Fprintf(m.profTxt, "Player: %s", player.Name)
+	} else {
+		fmt.Fprint(m.profTxt, "Not saving")
 	}
-	shuffle(&fallacyKeys)
-	for j := range fallacyKeys {
-		found := false
-		for k := range result {
-			if fallacyKeys[j] == result[k] {
-				found = true
-				break
-			}
-		}
-		if !found {
-			result = append(result, fallacyKeys[j])
-			break
-		}
+}
+
+func (m *menuScene) update(dt float64) {
+	m.focus.update(&m.start, &m.practice, &m.study, &m.blitz, &m.survival, &m.tutorial, &m.quit, &m.profile, &m.stats, &m.replays)
+	switch {
+	case m.start.check():
+		m.st.push(newQuizScene(m.st, modeNormal))
+	case m.practice.check():
+		m.st.push(newPracticeMenu(m.st))
+	case m.study.check():
+		m.st.push(newQuizScene(m.st, modeStudy))
+	case m.blitz.check():
+		m.st.push(newBlitzMenu(m.st))
+	case m.survival.check():
+		m.st.push(newQuizScene(m.st, modeSurvival))
+	case m.tutorial.check():
+		m.st.push(newQuizScene(m.st, modeTutorial))
+	case m.quit.check():
+		m.st.pop()
+	case m.profile.check() && player != nil:
+		m.st.push(newProfileScene(m.st))
+	case m.stats.check() && player != nil:
+		m.st.push(newStatsScene(m.st))
+	case m.replays.check():
+		m.st.push(newReplayList(m.st))
 	}
-	shuffle(&result)
-	return result
 }
 
-func menu(win *pixelgl.Window) {
-	atlas := text.NewAtlas(basicfont.Face7x13, text.ASCII)
-resize:
-	r := win.Bounds()
+func (m *menuScene) draw() {
+	win := m.st.win
 	// Title
-	titlePos := pixel.V(r.W()/2, 8.5*r.H()/11)
-	titleTxt := text.New(pixel.ZV, atlas)
-	fmt.Fprint(titleTxt, "Fallacy Quest")
+	drawText(win, m.titleTxt, m.titlePos, 5)
 	// Start
-	startPos := pixel.V(r.W()/2, 6*r.H()/11)
-	startButton := newButton(win, pixel.R(startPos.X-winX*100/origX, startPos.Y-winY*50/origY, startPos.X+winX*100/origX, startPos.Y+winY*50/origY), colornames.Sandybrown, colornames.Rosybrown)
-	startTxt := text.New(pixel.ZV, atlas)
-	fmt.Fprint(startTxt, "Start")
+	m.start.draw()
+	drawText(win, m.startTxt, m.start.rect.Center(), 3)
+	// Practice
+	m.practice.draw()
+	drawText(win, m.practiceTxt, m.practice.rect.Center(), 3)
+	// Study
+	m.study.draw()
+	drawText(win, m.studyTxt, m.study.rect.Center(), 3)
+	// Blitz
+	m.blitz.draw()
+	drawText(win, m.blitzTxt, m.blitz.rect.Center(), 3)
+	// Survival
+	m.survival.draw()
+	drawText(win, m.survTxt, m.survival.rect.Center(), 3)
 	// Tutorial
-	tutorialPos := pixel.V(r.W()/2, 4*r.H()/11)
-	tutorialButton := newButton(win, pixel.R(tutorialPos.X-winX*100/origX, tutorialPos.Y-winY*50/origY, tutorialPos.X+winX*100/origX, tutorialPos.Y+winY*50/origY), colornames.Sandybrown, colornames.Rosybrown)
-	tutorialTxt := text.New(pixel.ZV, atlas)
-	fmt.Fprint(tutorialTxt, "Tutorial")
+	m.tutorial.draw()
+	drawText(win, m.tutTxt, m.tutorial.rect.Center(), 3)
 	// Quit
-	quitPos := pixel.V(r.W()/2, 2*r.H()/11)
-	quitButton := newButton(win, pixel.R(quitPos.X-winX*100/origX, quitPos.Y-winY*50/origY, quitPos.X+winX*100/origX, quitPos.Y+winY*50/origY), colornames.Sandybrown, colornames.Rosybrown)
-	quitTxt := text.New(pixel.ZV, atlas)
-	fmt.Fprint(quitTxt, "Quit")
-	for !win.Closed() {
-		win.Clear(background)
-		// Title Draw
-		titleTxt.Draw(win, pixel.IM.ScaledXY(titleTxt.Bounds().Center(), pixel.V(winX*5/origX, winY*5/origY)).Moved(titlePos.Sub(titleTxt.Bounds().Center())))
-		// Start Check
-		if startButton.check() {
-			start(win, false)
-			goto resize
-		}
-		startTxt.Draw(win, pixel.IM.ScaledXY(startTxt.Bounds().Center(), pixel.V(winX*3/origX, winY*3/origY)).Moved(startPos.Sub(startTxt.Bounds().Center())))
-		// Tutorial Check
-		if tutorialButton.check() {
-			start(win, true)
-			goto resize
-		}
-		tutorialTxt.Draw(win, pixel.IM.ScaledXY(tutorialTxt.Bounds().Center(), pixel.V(winX*3/origX, winY*3/origY)).Moved(tutorialPos.Sub(tutorialTxt.Bounds().Center())))
-		// Quit Check
-		if quitButton.check() {
+	m.quit.draw()
+	drawText(win, m.quitTxt, m.quit.rect.Center(), 3)
+	// Profile
+	m.profile.draw()
+	drawText(win, m.profTxt, m.profile.rect.Center(), 2)
+	// Stats
+	if player != nil {
+		m.stats.draw()
+		drawText(win, m.statsTxt, m.stats.rect.Center(), 2)
+	}
+	// Replays
+	m.replays.draw()
+	drawText(win, m.replaysTxt, m.replays.rect.Center(), 2)
+}
+
+var tutorialQuestion = content.Question{
+	Name:    "analogy",
+	Phrases: []string{"Mice", "are afraid", "of cats", "therefore", "humans", "are afraid", "of cats."},
+	Ans:     []int{0, 4},
+}
+
+func joinRoles(roles []string) string {
+	switch len(roles) {
+	case 0:
+		return ""
+	case 1:
+		return roles[0]
+	case 2:
+		return roles[0] + " and " + roles[1]
+	}
+	return strings.Join(roles[:len(roles)-1], ", ") + ", and " + roles[len(roles)-1]
+}
+
+// fallacyName is the name of the fallacy with key, or the key itself if the
+// catalog doesn't have it.
+func fallacyName(key string) string {
+	if f := catalog.Get(key); f != nil {
+		return f.Name
+	}
+	return key
+}
+
+// tutorialPages walks through the tutorial question. Content loaded with
+// -content may lack the fallacies it mentions, or their roles, so those
+// pages make do with what the catalog has.
+func tutorialPages() []string {
+	analogy := fallacyName(tutorialQuestion.Name)
+	pages := []string{
+		"Welcome to Fallacy Quest!",
+		"This is a fallacy, but which one?",
+		"First, find the correct answer",
+		fmt.Sprintf(`In this case, that's "%s"`, analogy),
+		"So now, click the circle next to the answer",
+		"The number in parentheses next to the answer...",
+		`...tells you how many "choices" it takes`,
+		"The choices determine what the fallacy actually is",
+		fmt.Sprintf("So for a %s, that would be...", analogy),
+		"...the two things being analogized",
+	}
+	if accident := catalog.Get("accident"); accident != nil && len(accident.Roles) > 0 {
+		pages = append(pages,
+			fmt.Sprintf("For an %s it would be...", accident.Name),
+			"..."+joinRoles(accident.Roles),
+		)
+	}
+	pages = append(pages,
+		"Pretty easy right?",
+		`Well, once you've figured out the "choices"...`,
+		"...you can go ahead on click on them to select them",
+		`In this case, the choices would be "Mice" and "humans"...`,
+		"...since those are the things being analogized weakly",
+		`So go on and click the words "Mice" and "humans" in the text below`,
+		"Once you've bubbled in your answer above...",
+		"...and selected your choices below...",
+		`...you can check your answer by clicking on the green "Check" button`,
+		"If your answer is correct, you'll win some points and move on",
+		"If not, don't worry!",
+		"You'll be given as many chances as you need to retry the question",
+		"But if you're stuck, you can always skip the question",
+		"Have fun!",
+		`The following is a description of fallacies and their "choices"`,
+	)
+	for _, f := range catalog.Fallacies {
+		head := fmt.Sprintf("%s (%d): ", f.Name, f.Args)
+		roles := joinRoles(f.Roles)
+		if roles == "" {
+			pages = append(pages, strings.TrimSuffix(head, ": "))
+			continue
+		}
+		roles = strings.ToUpper(roles[:1]) + roles[1:]
+		if len(head)+len(roles) > 70 {
+			pages = append(pages, head+"...", "..."+roles)
+		} else {
+			pages = append(pages, head+roles)
+		}
+	}
+	return pages
+}
+
+// fixedSeed is the seed given with -seed, if any.
+var fixedSeed *int64
+
+// newSeed returns the seed for a new session: the one given on the command
+// line, or else one from the clock.
+func newSeed() int64 {
+	if fixedSeed != nil {
+		return *fixedSeed
+	}
+	return time.Now().UnixNano()
+}
+
+// Session modes. The mode is saved with each session and keys its high
+// score board.
+const (
+	modeNormal   = "normal"
+	modeStudy    = "study"
+	modeBlitz    = "blitz"
+	modeSurvival = "survival"
+	modePractice = "practice"
+	modeTutorial = "tutorial"
+	// modeClassroom plays a round sent by a classroom host.
+	modeClassroom = "classroom"
+)
+
+const (
+	survivalLives = 3
+	// survivalStep is how long a combo moves survival up a difficulty level.
+	survivalStep = 3
+)
+
+type quizScene struct {
+	st       *sceneStack
+	mode     string
+	tutorial bool
+	seconds  int
+	filter   quest.Filter
+	total    int
+	seed     int64
+	s        *quest.Session
+	f        fallacy
+	c        choice
+	tutStep  int
+	pages    []string
+	// focus is the keyboard cursor over the choices and then the phrases,
+	// or -1 before any key has moved it.
+	focus int
+	// adapted is set when the round was fitted to the player's profile,
+	// so its seed alone doesn't say what was asked.
+	adapted bool
+	// events hears from the session. Played sessions pass their events on
+	// to the bus and record them with their input in rec; a replayed one
+	// replays instead and keeps its events to itself.
+	events    events.Bus
+	rec       *replay.Recording
+	replaying *replay.Recording
+	// round is the classroom round being played, and client the host it
+	// came from, which hears the session's events.
+	round  *classroom.Round
+	client *classroom.Client
+	// err is why the session couldn't start, if it couldn't. The scene
+	// then only shows it until the player goes back.
+	err error
+	// Widgets
+	back, check, skip, tutNext button
+	backIcon                   *imdraw.IMDraw
+	checkTxt, skipTxt          *text.Text
+	progressTxt, scoreTxt      *text.Text
+	feedbackTxt, explainTxt    *text.Text
+	tutTxt, tutNextTxt         *text.Text
+	errTxt                     *text.Text
+}
+
+func newQuizScene(st *sceneStack, mode string) *quizScene {
+	return &quizScene{st: st, mode: mode, tutorial: mode == modeTutorial}
+}
+
+// newBlitzScene is a round of as many questions as can be answered in the
+// given number of seconds.
+func newBlitzScene(st *sceneStack, seconds int) *quizScene {
+	q := newQuizScene(st, modeBlitz)
+	q.seconds = seconds
+	return q
+}
+
+// newPracticeScene is a round of total questions that pass filter.
+func newPracticeScene(st *sceneStack, filter quest.Filter, total int) *quizScene {
+	q := newQuizScene(st, modePractice)
+	q.filter = filter
+	q.total = total
+	return q
+}
+
+func (q *quizScene) enter() {
+	q.seed = newSeed()
+	scoring := settings.Scoring
+	if q.round != nil {
+		q.seed = q.round.Seed
+		scoring = q.round.Scoring
+	}
+	if q.client != nil {
+		q.events.Subscribe(q.client)
+	}
+	if q.replaying != nil {
+		q.seed = q.replaying.Seed
+		scoring = q.replaying.Scoring
+	} else {
+		q.events.Subscribe(events.SinkFunc(func(r events.Record) {
+			bus.Emit(r.Event)
+		}))
+		if !q.tutorial {
+			q.rec = &replay.Recording{Mode: q.mode, Seed: q.seed, Seconds: q.seconds, Total: q.total, Filter: q.filter, Scoring: scoring}
+			if player != nil {
+				q.rec.Player = player.Name
+			}
+			q.events.Subscribe(q.rec)
+		}
+	}
+	scorer, err := scoring.NewScorer()
+	if err != nil {
+		q.fail(err)
+		return
+	}
+	rng := rand.New(rand.NewSource(q.seed))
+	opts := quest.Options{Scorer: scorer, Rand: rng, Events: &q.events}
+	pool, total := questions, 10
+	switch q.mode {
+	case modeTutorial:
+		pool, total = []content.Question{tutorialQuestion}, 1
+		opts = quest.Options{Rand: rng, Events: &q.events}
+	case modeStudy:
+		opts.Picker = studyPicker()
+	case modePractice:
+		deck, err := quest.NewDeck(catalog, questions, q.filter, rng)
+		if err != nil {
+			q.fail(err)
+			return
+		}
+		opts.Picker = deck
+		total = q.total
+	case modeSurvival:
+		ramp, err := quest.NewRamp(catalog, questions, survivalStep, rng)
+		if err != nil {
+			q.fail(err)
+			return
+		}
+		opts.Picker = ramp
+		opts.Lives = survivalLives
+		total = 0
+	case modeClassroom:
+		total = q.total
+		if q.round != nil {
+			seq := q.round.Sequence()
+			opts.Picker, opts.Distractor = seq, seq
+		}
+	case modeBlitz:
+		deck, err := quest.NewDeck(catalog, questions, quest.Filter{}, rng)
+		if err != nil {
+			q.fail(err)
+			return
+		}
+		blitz := scoring.Blitz
+		opts.Scorer = &blitz
+		opts.Picker = deck
+		opts.TimeLimit = float64(q.seconds)
+		total = 0
+	case modeNormal:
+		if settings.Adaptive && fixedSeed == nil && player != nil {
+			opts.Picker = &adaptivePicker{book: player.Ratings.Copy()}
+			q.adapted = true
+			break
+		}
+		deck, err := quest.NewDeck(catalog, questions, quest.Filter{}, rng)
+		if err != nil {
+			q.fail(err)
 			return
 		}
-		quitTxt.Draw(win, pixel.IM.ScaledXY(quitTxt.Bounds().Center(), pixel.V(winX*3/origX, winY*3/origY)).Moved(quitPos.Sub(quitTxt.Bounds().Center())))
-		win.Update()
-		if resized(win) {
-			goto resize
-		}
-	}
-}
-
-func start(win *pixelgl.Window, tutorial bool) {
-	atlas := text.NewAtlas(basicfont.Face7x13, text.ASCII)
-reset:
-	score := 0.0
-	combo := 0
-	count := 1
-	total := 10
-	if tutorial {
-		total = 1
-	}
-	tutStep := 0
-reload:
-	timer := 0.0
-	possibleGain := 10 + float64(combo)*score/2
-	pointsScored := 0.0
-	var f fallacy
-	f = randFallacy(win)
-	if tutorial {
-		f.name = "analogy"
-		f.ans = []int{0, 4}
-		f.phrases = []string{"Mice", "are afraid", "of cats", "therefore", "humans", "are afraid", "of cats."}
-		f.calcTexts()
-	}
-	choices := getFallacyChoices(f.name)
+		opts.Picker = deck
+	}
+	if q.replaying != nil {
+		script := replay.Script(q.replaying)
+		opts.Picker, opts.Distractor = script, script
+	}
+	start := events.SessionStart{Mode: q.mode, Seed: q.seed, Length: total, TimeLimit: opts.TimeLimit, Lives: opts.Lives}
+	switch {
+	case q.replaying != nil:
+		start.Player = q.replaying.Player
+	case player != nil:
+		start.Player = player.Name
+	}
+	q.events.Emit(start)
+	q.s = quest.NewSession(catalog, pool, total, opts)
+	q.tutStep = 0
+	if q.tutorial {
+		q.pages = tutorialPages()
+	}
+	q.checkTxt = text.New(pixel.ZV, atlas)
+	q.skipTxt = text.New(pixel.ZV, atlas)
+	q.progressTxt = text.New(pixel.ZV, atlas)
+	q.scoreTxt = text.New(pixel.ZV, atlas)
+	q.feedbackTxt = text.New(pixel.ZV, atlas)
+	q.explainTxt = text.New(pixel.ZV, atlas)
+	q.tutTxt = text.New(pixel.ZV, atlas)
+	q.tutTxt.Color = colornames.Black
+	q.tutNextTxt = text.New(pixel.ZV, atlas)
+	q.tutNextTxt.Color = colornames.Green
+	fmt.Fprint(q.tutNextTxt, "Next ->")
+	q.load()
+}
+
+// fail keeps the scene from starting a session and shows err instead.
+func (q *quizScene) fail(err error) {
+	q.err = err
+	q.errTxt = text.New(pixel.ZV, atlas)
+	q.errTxt.Color = colornames.Yellow
+	fmt.Fprintf(q.errTxt, "Can't start this round: %v", err)
+}
+
+func (q *quizScene) exit() {
+	if q.err != nil {
+		return
+	}
+	q.events.Emit(events.SessionEnd{Score: q.s.Score, Questions: len(q.s.Answers), Elapsed: q.s.Elapsed, Finished: q.s.Done})
+	if q.rec != nil && len(q.rec.Frames) > 0 {
+		saveReplay(q.rec)
+	}
+}
+
+// load builds the widgets for the session's current question.
+func (q *quizScene) load() {
+	q.f = newFallacy(q.st.win, q.s)
 	var fallacyList []string
-	for _, choice := range choices {
-		fallacyList = append(fallacyList, fmt.Sprintf("%v (%d)", fallacyNames[choice].fullName, fallacyNames[choice].argCount))
+	for _, choice := range q.s.Choices {
+		fallacyList = append(fallacyList, fmt.Sprintf("%v (%d)", catalog.Get(choice).Name, catalog.Get(choice).Args))
 	}
-	c := newChoice(win, fallacyList, choices)
-resize:
-	correct := false
-	c.setCenter(winX/3, 4*winY/5)
-	f.calcTexts()
-	r := win.Bounds()
+	q.c = newChoice(q.st.win, fallacyList, q.s.Choices)
+	q.focus = -1
+}
+
+// moveFocus steps the keyboard cursor by delta, wrapping around.
+func (q *quizScene) moveFocus(delta int) {
+	n := len(q.s.Choices) + len(q.s.Question.Phrases)
+	switch {
+	case q.focus < 0 && delta > 0:
+		q.focus = 0
+	case q.focus < 0:
+		q.focus = n - 1
+	default:
+		q.focus = (q.focus + delta + n) % n
+	}
+	q.c.focus = q.focus
+	q.f.focus = q.focus - len(q.s.Choices)
+}
+
+// keys handles control without the mouse: Pick actions choose a fallacy,
+// Next and Prev move the focus, Toggle selects what has focus, Confirm
+// checks and Skip skips.
+func (q *quizScene) keys() (check, skip bool) {
+	for i := range q.s.Choices {
+		if i < 9 && in.JustPressed(input.Pick1+input.Action(i)) {
+			q.s.SelectChoice(i)
+		}
+	}
+	switch {
+	case in.JustPressed(input.Next):
+		q.moveFocus(1)
+	case in.JustPressed(input.Prev):
+		q.moveFocus(-1)
+	}
+	if in.JustPressed(input.Toggle) && q.focus >= 0 {
+		if q.focus < len(q.s.Choices) {
+			q.s.SelectChoice(q.focus)
+		} else {
+			q.s.TogglePhrase(q.focus - len(q.s.Choices))
+		}
+	}
+	confirm := in.JustPressed(input.Confirm)
+	// Once the answer is right, Confirm continues like the Continue button.
+	return confirm && !q.s.Correct, in.JustPressed(input.Skip) || confirm && q.s.Correct
+}
+
+func (q *quizScene) onResize() {
+	win := q.st.win
+	if q.err != nil {
+		q.back, q.backIcon = backButton(win)
+		return
+	}
+	q.c.setCenter(winX/3, 4*winY/5)
+	q.f.calcTexts()
 	// Back Button
-	back := newButton(win, pixel.R(r.Min.X, r.Max.Y-winY*100/origY, r.Min.X+winX*100/origX, r.Max.Y), colornames.Sandybrown, colornames.Rosybrown)
-	backIcon := imdraw.New(nil)
-	backIcon.Push(pixel.V(r.Min.X+winX*10/origX, r.Max.Y-winY*50/origY), pixel.V(r.Min.X+winX*90/origX, r.Max.Y-winY*10/origY), pixel.V(r.Min.X+winX*90/origX, r.Max.Y-winY*90/origY))
-	backIcon.Polygon(0)
+	q.back, q.backIcon = backButton(win)
 	// Check Button
-	check := newButton(win, pixel.R(winX/2-winX*210/origX, winY/4-winY*50/origY, winX/2-winX*10/origX, winY/4+winY*50/origY), colornames.Green, colornames.Darkgreen)
-	checkTxt := text.New(pixel.ZV, atlas)
-	checkTxt.Clear()
-	fmt.Fprint(checkTxt, "Check")
+	q.check = newButton(win, pixel.R(winX/2-winX*210/origX, winY/4-winY*50/origY, winX/2-winX*10/origX, winY/4+winY*50/origY), colornames.Green, colornames.Darkgreen)
 	// Skip Button
-	skip := newButton(win, pixel.R(winX/2+winX*10/origX, winY/4-winY*50/origY, winX/2+winX*210/origX, winY/4+winY*50/origY), colornames.Red, colornames.Darkred)
-	skipTxt := text.New(pixel.ZV, atlas)
-	skipTxt.Clear()
-	fmt.Fprint(skipTxt, "Skip")
-	// Progress Text
-	progressTxt := text.New(pixel.ZV, atlas)
-	progressTxt.Clear()
-	fmt.Fprintf(progressTxt, "%d/%d", count, total)
-	// Score Text
-	scoreTxt := text.New(pixel.ZV, atlas)
-	scoreTxt.Clear()
-	fmt.Fprintf(scoreTxt, "Score: %.2f", score)
-	last := time.Now()
-	// Tutorial Text
-	tutTxt := text.New(pixel.ZV, atlas)
-	tutTxt.Color = colornames.Black
-	tutNext := newButton(win, pixel.R(winX/2-winX*60/origX, 10.5*winY/17-winY*25/origY, winX/2+winX*60/origX, 10.5*winY/17+winY*25/origY), colornames.Blue, colornames.Darkblue)
-	tutNextTxt := text.New(pixel.ZV, atlas)
-	tutNextTxt.Color = colornames.Green
-	fmt.Fprint(tutNextTxt, "Next ->")
-	for !win.Closed() {
-		// Delta Time
-		dt := time.Since(last).Seconds()
-		last = time.Now()
-		// Clear background
-		win.Clear(background)
-		// Update timer
-		timer += dt
-		// Choices
-		c.draw()
-		// Check
-		if check.check() {
-			for _, v := range c.buttons {
-				if v.pressed {
-					correct = v.name == f.name
-					break
-				}
-			}
-			var selected []int
-			for i, v := range f.texts {
-				if v.active {
-					selected = append(selected, i)
-				}
-			}
-			if len(f.ans) != len(selected) {
-				correct = false
-			} else {
-				for _, v := range selected {
-					found := false
-					for _, k := range f.ans {
-						if v == k {
-							found = true
-						}
-					}
-					if !found {
-						correct = false
-					}
-				}
-			}
-			if correct { // Correct
-				pointsScored = math.Max(possibleGain*(4/(timer+5)+.2), 0)
-				checkTxt.Clear()
-				fmt.Fprint(checkTxt, "Correct!")
-				check.unpressedColor = color.Transparent
-				check.pressedColor = color.Transparent
-				skipTxt.Clear()
-				fmt.Fprint(skipTxt, "Continue")
-				skip.unpressedColor = colornames.Blue
-				skip.pressedColor = colornames.Darkblue
-			} else { // Incorrect
-				combo = 0
-				possibleGain -= possibleGain / 16
-				score -= score / 16
-				score = math.Max(score, 0)
-				scoreTxt.Clear()
-				fmt.Fprintf(scoreTxt, "Score: %.2f", score)
-			}
+	q.skip = newButton(win, pixel.R(winX/2+winX*10/origX, winY/4-winY*50/origY, winX/2+winX*210/origX, winY/4+winY*50/origY), colornames.Red, colornames.Darkred)
+	q.relabel()
+	// Tutorial
+	q.tutNext = newButton(win, pixel.R(winX/2-winX*60/origX, 10.5*winY/17-winY*25/origY, winX/2+winX*60/origX, 10.5*winY/17+winY*25/origY), colornames.Blue, colornames.Darkblue)
+}
+
+// relabel sets the Check and Skip buttons to match the answer state.
+func (q *quizScene) relabel() {
+	q.checkTxt.Clear()
+	q.skipTxt.Clear()
+	q.explainTxt.Clear()
+	if q.s.Correct {
+		fmt.Fprint(q.explainTxt, explanation(q.s.Question))
+		fmt.Fprint(q.checkTxt, "Correct!")
+		q.check.unpressedColor = color.Transparent
+		q.check.pressedColor = color.Transparent
+		fmt.Fprint(q.skipTxt, "Continue")
+		q.skip.unpressedColor = colornames.Blue
+		q.skip.pressedColor = colornames.Darkblue
+	} else {
+		fmt.Fprint(q.checkTxt, "Check")
+		fmt.Fprint(q.skipTxt, "Skip")
+	}
+}
+
+// board names the high score board for the session, or "" if it has none.
+// Each mode has its own boards, one per round length.
+func (q *quizScene) board() string {
+	switch q.mode {
+	case modeTutorial, modePractice, modeClassroom:
+		return ""
+	case modeBlitz:
+		return highscore.Key(q.mode, q.seconds)
+	case modeSurvival:
+		return highscore.Key(q.mode, survivalLives)
+	}
+	return highscore.Key(q.mode, q.s.Total)
+}
+
+// replayableSeed is the session's seed if -seed would play the same round
+// again, or nil. Study rounds are scheduled from the profile and the clock,
+// classroom rounds come from the host, and adapted rounds depend on the
+// profile as it was.
+func (q *quizScene) replayableSeed() *int64 {
+	switch {
+	case q.mode == modeStudy, q.mode == modeTutorial, q.mode == modeClassroom, q.adapted:
+		return nil
+	}
+	return &q.seed
+}
+
+// finish moves on to the results once the session is done. A replay just
+// ends.
+func (q *quizScene) finish() {
+	if q.replaying != nil {
+		q.st.pop()
+		return
+	}
+	if q.mode == modeClassroom {
+		q.st.replace(newWinScene(q.st, q.s, q.board(), q.replayableSeed(), nil))
+		return
+	}
+	q.st.replace(newWinScene(q.st, q.s, q.board(), q.replayableSeed(), func() scene {
+		again := newQuizScene(q.st, q.mode)
+		again.seconds = q.seconds
+		again.filter = q.filter
+		again.total = q.total
+		return again
+	}))
+}
+
+func (q *quizScene) update(dt float64) {
+	if q.err != nil {
+		if q.back.check() || in.JustPressed(input.Back) {
+			q.st.pop()
 		}
-		checkTxt.Draw(win, pixel.IM.ScaledXY(checkTxt.Bounds().Center(), pixel.V(winX*3/origX, winY*3/origY)).Moved(check.rect.Center().Sub(checkTxt.Bounds().Center())))
-		// Skip
-		if skip.check() {
-			if correct {
-				combo += 1
-				score += pointsScored
-			} else {
-				combo = 0
-				score -= score / 4
-			}
-			count += 1
-			if count > total {
-				retry := winScreen(win, score)
-				if retry {
-					goto reset
-				} else {
-					return
-				}
-			}
-			goto reload
-		}
-		skipTxt.Draw(win, pixel.IM.ScaledXY(skipTxt.Bounds().Center(), pixel.V(winX*3/origX, winY*3/origY)).Moved(skip.rect.Center().Sub(skipTxt.Bounds().Center())))
-		// Progress
-		progressTxt.Draw(win, pixel.IM.ScaledXY(progressTxt.Bounds().Center(), pixel.V(winX*3/origX, winY*3/origY)).Moved(pixel.V(winX/2, 10*winY/11).Sub(progressTxt.Bounds().Center())))
-		// Score
-		scoreTxt.Draw(win, pixel.IM.ScaledXY(scoreTxt.Bounds().Center(), pixel.V(winX*3/origX, winY*3/origY)).Moved(pixel.V(winX/2, winY/9).Sub(scoreTxt.Bounds().Center())))
-		// Back
-		if back.check() {
+		return
+	}
+	if q.rec != nil {
+		q.rec.Add(dt, in, pixel.V(winX, winY))
+	}
+	// Update timer
+	q.s.Tick(dt)
+	if q.s.Done {
+		q.finish()
+		return
+	}
+	// Keyboard
+	checkKey, skipKey := q.keys()
+	// Choices
+	q.c.selected = q.s.Chosen
+	if q.c.update() {
+		q.s.SelectChoice(q.c.selected)
+	}
+	// Check
+	if q.check.check() || checkKey {
+		r := q.s.Check()
+		q.feedbackTxt.Clear()
+		if r.Correct {
+			q.relabel()
+		} else {
+			fmt.Fprint(q.feedbackTxt, feedback(r))
+		}
+	}
+	// Skip
+	if q.skip.check() || skipKey {
+		q.s.Skip()
+		if q.s.Done {
+			q.finish()
 			return
 		}
-		backIcon.Draw(win)
-		// Fallacies
-		f.check()
-		// Tutorial
-		if tutorial {
-			//tutCover.Draw(win)
-			if tutStep <= 45 && tutNext.check() {
-				tutStep += 1
-			}
-			if tutStep <= 45 {
-				tutNextTxt.Draw(win, pixel.IM.ScaledXY(tutNextTxt.Bounds().Center(), pixel.V(winX*2/origX, winY*2/origY)).Moved(tutNext.rect.Center().Sub(tutNextTxt.Bounds().Center())))
-			}
-			tutTxt.Clear()
-			switch tutStep {
-			case 0:
-				fmt.Fprint(tutTxt, "Welcome to Fallacy Quest!")
-				break
-			case 1:
-				fmt.Fprint(tutTxt, "This is a fallacy, but which one?")
-				break
-			case 2:
-				fmt.Fprint(tutTxt, "First, find the correct answer")
-				break
-			case 3:
-				fmt.Fprint(tutTxt, `In this case, that's "Weak Analogy"`)
-				break
-			case 4:
-				fmt.Fprint(tutTxt, "So now, click the circle next to the answer")
-				break
-			case 5:
-				fmt.Fprint(tutTxt, "The number in parentheses next to the answer...")
-				break
-			case 6:
-				fmt.Fprint(tutTxt, `...tells you how many "choices" it takes`)
-				break
-			case 7:
-				fmt.Fprint(tutTxt, "The choices determine what the fallacy actually is")
-				break
-			case 8:
-				fmt.Fprint(tutTxt, "So for a Weak Analogy, that would be...")
-				break
-			case 9:
-				fmt.Fprint(tutTxt, "...the two things being analogized")
-				break
-			case 10:
-				fmt.Fprint(tutTxt, "For an Accident it would be...")
-				break
-			case 11:
-				fmt.Fprint(tutTxt, "...the generalization and the exceptional case")
-				break
-			case 12:
-				fmt.Fprint(tutTxt, "Pretty easy right?")
-				break
-			case 13:
-				fmt.Fprint(tutTxt, `Well, once you've figured out the "choices"...`)
-				break
-			case 14:
-				fmt.Fprint(tutTxt, "...you can go ahead on click on them to select them")
-				break
-			case 15:
-				fmt.Fprint(tutTxt, `In this case, the choices would be "Mice" and "humans"...`)
-				break
-			case 16:
-				fmt.Fprint(tutTxt, "...since those are the things being analogized weakly")
-				break
-			case 17:
-				fmt.Fprint(tutTxt, `So go on and click the words "Mice" and "humans" in the text below`)
-				break
-			case 18:
-				fmt.Fprint(tutTxt, "Once you've bubbled in your answer above...")
-				break
-			case 19:
-				fmt.Fprint(tutTxt, "...and selected your choices below...")
-				break
-			case 20:
-				fmt.Fprint(tutTxt, `...you can check your answer by clicking on the green "Check" button`)
-				break
-			case 21:
-				fmt.Fprint(tutTxt, "If your answer is correct, you'll win some points and move on")
-				break
-			case 22:
-				fmt.Fprint(tutTxt, "If not, don't worry!")
-				break
-			case 23:
-				fmt.Fprint(tutTxt, "You'll be given as many chances as you need to retry the question")
-				break
-			case 24:
-				fmt.Fprint(tutTxt, "But if you're stuck, you can always skip the question")
-				break
-			case 25:
-				fmt.Fprint(tutTxt, "Have fun!")
-				break
-			case 26:
-				fmt.Fprint(tutTxt, `The following is a description of fallacies and their "choices"`)
-				break
-			case 27:
-				fmt.Fprint(tutTxt, "Argumentum ad Hominem (1): The insult or attack")
-				break
-			case 28:
-				fmt.Fprint(tutTxt, "Straw Man (2): The actual argument and the strawman argument")
-				break
-			case 29:
-				fmt.Fprint(tutTxt, "Appeal to Emotion (1): The appeal to emotion")
-				break
-			case 30:
-				fmt.Fprint(tutTxt, "Weak Analogy (2): The statements being analogized")
-				break
-			case 31:
-				fmt.Fprint(tutTxt, "Hasty Generalization (2): The actual event and the generalization")
-				break
-			case 32:
-				fmt.Fprint(tutTxt, "Accident (2): The generalization and the exceptional case")
-				break
-			case 33:
-				fmt.Fprint(tutTxt, "Post Hoc Ergo Propter Hoc (2): The two events being compared")
-				break
-			case 34:
-				fmt.Fprint(tutTxt, "Cum Hoc Ergo Propter Hoc (2): The two events being compared")
-				break
-			case 35:
-				fmt.Fprint(tutTxt, "Slippery Slope (2): The initial event, and the slippery slope")
-				break
-			case 36:
-				fmt.Fprint(tutTxt, "Fallacious Appeal to Authority (1): The false authority")
-				break
-			case 37:
-				fmt.Fprint(tutTxt, "Fallacious Appeal to Popularity (1): The populace")
-				break
-			case 38:
-				fmt.Fprint(tutTxt, "Affirming the Consequent (2): ...")
-				break
-			case 39:
-				fmt.Fprint(tutTxt, "...The affirmed consequent, and the concluded antecedent")
-				break
-			case 40:
-				fmt.Fprint(tutTxt, "Denying the Antecedent (2): ...")
-				break
-			case 41:
-				fmt.Fprint(tutTxt, "...The denied antecedent, and the concluded consequent")
-				break
-			case 42:
-				fmt.Fprint(tutTxt, "Undistributed Middle (2): The fallacious elements")
-				break
-			case 43:
-				fmt.Fprint(tutTxt, "Equivocation (1): The ambiguous phrase")
-				break
-			case 44:
-				fmt.Fprint(tutTxt, "Combination (3): The two addends, and the resultant")
-				break
-			case 45:
-				fmt.Fprint(tutTxt, "Division (3): The original, and the two resultants")
-			}
-			tutTxt.Draw(win, pixel.IM.ScaledXY(tutTxt.Bounds().Center(), pixel.V(winX*2/origX, winY*2/origY)).Moved(pixel.V(winX/2, 11.5*winY/17).Sub(tutTxt.Bounds().Center())))
-		}
-		// Update
-		win.Update()
-		// Resize
-		if resized(win) {
-			goto resize
-		}
-	}
-}
-
-func winScreen(win *pixelgl.Window, score float64) bool {
-	atlas := text.NewAtlas(basicfont.Face7x13, text.ASCII)
-resize:
-	congratsTxt := text.New(pixel.ZV, atlas)
-	fmt.Fprint(congratsTxt, "Congratulations!")
-	// Score Text
-	scoreTxt := text.New(pixel.ZV, atlas)
-	fmt.Fprintf(scoreTxt, "Score: %.2f", score)
-	// Menu Button
-	menu := newButton(win, pixel.R(winX/2-winX*210/origX, winY/5-winY*50/origY, winX/2-winX*10/origX, winY/5+winY*50/origY), colornames.Sandybrown, colornames.Rosybrown)
-	menuTxt := text.New(pixel.ZV, atlas)
-	fmt.Fprint(menuTxt, "Menu")
-	// Replay
-	replay := newButton(win, pixel.R(winX/2+winX*10/origX, winY/5-winY*50/origY, winX/2+winX*210/origX, winY/5+winY*50/origY), colornames.Green, colornames.Darkgreen)
-	replayTxt := text.New(pixel.ZV, atlas)
-	fmt.Fprint(replayTxt, "Replay")
-	for !win.Closed() {
-		win.Clear(background)
-		// Congrats
-		congratsTxt.Draw(win, pixel.IM.ScaledXY(congratsTxt.Bounds().Center(), pixel.V(winX*5/origX, winY*5/origY)).Moved(pixel.V(winX/2, 4*winY/5).Sub(congratsTxt.Bounds().Center())))
-		// Score
-		scoreTxt.Draw(win, pixel.IM.ScaledXY(scoreTxt.Bounds().Center(), pixel.V(winX*5/origX, winY*5/origY)).Moved(pixel.V(winX/2, 3*winY/5).Sub(scoreTxt.Bounds().Center())))
-		// Menu
-		if menu.check() {
-			return false
-		}
-		menuTxt.Draw(win, pixel.IM.ScaledXY(menuTxt.Bounds().Center(), pixel.V(winX*3/origX, winY*3/origY)).Moved(menu.rect.Center().Sub(menuTxt.Bounds().Center())))
-		// Replay
-		if replay.check() {
-			return true
-		}
-		replayTxt.Draw(win, pixel.IM.ScaledXY(replayTxt.Bounds().Center(), pixel.V(winX*3/origX, winY*3/origY)).Moved(replay.rect.Center().Sub(replayTxt.Bounds().Center())))
-		win.Update()
-		if resized(win) {
-			goto resize
-		}
-	}
-	return false
+		q.feedbackTxt.Clear()
+		q.load()
+		q.onResize()
+		return
+	}
+	// Back
+	if q.back.check() || in.JustPressed(input.Back) {
+		q.st.pop()
+		return
+	}
+	// Fallacies
+	q.f.update()
+	// Tutorial
+	if q.tutorial && q.tutStep < len(q.pages) && q.tutNext.check() {
+		q.tutStep += 1
+	}
+}
+
+func (q *quizScene) draw() {
+	win := q.st.win
+	if q.err != nil {
+		drawText(win, q.errTxt, pixel.V(winX/2, winY/2), 2)
+		q.back.draw()
+		q.backIcon.Draw(win)
+		return
+	}
+	// Choices, or why the answer is right once it is
+	if q.s.Correct {
+		drawText(win, q.explainTxt, pixel.V(winX/2, 3*winY/4), 1.5)
+	}
+	switch q.s.ChoiceMark {
+	case quest.Hit:
+		q.c.selectedColor = colornames.Limegreen
+	case quest.Wrong:
+		q.c.selectedColor = colornames.Red
+	default:
+		q.c.selectedColor = nil
+	}
+	if !q.s.Correct {
+		q.c.draw()
+	}
+	// Check
+	q.check.draw()
+	drawText(win, q.checkTxt, q.check.rect.Center(), 3)
+	// Skip
+	q.skip.draw()
+	drawText(win, q.skipTxt, q.skip.rect.Center(), 3)
+	// Feedback
+	drawText(win, q.feedbackTxt, pixel.V(winX/2, 3*winY/8), 2)
+	// Progress
+	q.progressTxt.Clear()
+	q.progressTxt.Color = colornames.White
+	if q.s.TimeLimit > 0 {
+		left := int(math.Ceil(q.s.Remaining()))
+		if left <= 10 {
+			q.progressTxt.Color = colornames.Yellow
+		}
+		fmt.Fprintf(q.progressTxt, "%d:%02d", left/60, left%60)
+	} else if q.s.Lives > 0 {
+		if q.s.LivesLeft() == 1 {
+			q.progressTxt.Color = colornames.Yellow
+		}
+		fmt.Fprintf(q.progressTxt, "#%d  Lives: %d", q.s.Count, q.s.LivesLeft())
+	} else {
+		fmt.Fprintf(q.progressTxt, "%d/%d", q.s.Count, q.s.Total)
+	}
+	drawText(win, q.progressTxt, pixel.V(winX/2, 10*winY/11), 3)
+	// Score
+	q.scoreTxt.Clear()
+	fmt.Fprintf(q.scoreTxt, "Score: %.2f", q.s.Score)
+	drawText(win, q.scoreTxt, pixel.V(winX/2, winY/9), 3)
+	// Back
+	q.back.draw()
+	q.backIcon.Draw(win)
+	// Fallacies
+	q.f.draw()
+	// Tutorial
+	if q.tutorial {
+		if q.tutStep < len(q.pages) {
+			q.tutNext.draw()
+			drawText(win, q.tutNextTxt, q.tutNext.rect.Center(), 2)
+		}
+		q.tutTxt.Clear()
+		if q.tutStep < len(q.pages) {
+			fmt.Fprint(q.tutTxt, q.pages[q.tutStep])
+		}
+		drawText(win, q.tutTxt, pixel.V(winX/2, 11.5*winY/17), 2)
+	}
+}
+
+// feedback says which part of a wrong answer was wrong.
+func feedback(r quest.Result) string {
+	verdict := "Wrong fallacy"
+	if r.Fallacy {
+		verdict = "Right fallacy"
+	}
+	found := fmt.Sprintf("%d of %d phrases", r.Hits, r.Hits+r.Missed)
+	if r.Extra > 0 {
+		found += fmt.Sprintf(" (+%d wrong)", r.Extra)
+	}
+	return fmt.Sprintf("%s, %s: %.0f%% credit", verdict, found, r.Credit*100)
+}
+
+// explanation names the fallacy, says why it applies and what each answer
+// phrase is. Questions without their own explanation fall back on the
+// catalog description.
+func explanation(q *content.Question) string {
+	f := catalog.Get(q.Name)
+	why := q.Explanation
+	if why == "" {
+		why = f.Description
+	}
+	lines := append([]string{f.Name, ""}, wrap(why, 70)...)
+	lines = append(lines, "")
+	for i, a := range q.Ans {
+		line := fmt.Sprintf(`"%s"`, strings.TrimPrefix(q.Phrases[a], "\n"))
+		if i < len(f.Roles) {
+			line += " is " + f.Roles[i]
+		}
+		if i < len(q.Notes) {
+			line += ": " + q.Notes[i]
+		}
+		lines = append(lines, wrap(line, 70)...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func wrap(s string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		if line != "" && len(line)+1+len(word) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
 }
 
 func run() {
@@ -825,11 +1120,125 @@ func run() {
 	if err != nil {
 		panic(err)
 	}
-	menu(win)
+	st := &sceneStack{win: win, src: input.NewWindow(win)}
+	st.push(newMenuScene(st))
+	if startReplay != nil {
+		st.push(newReplayScene(st, startReplay))
+	}
+	switch {
+	case hostAddr != "":
+		st.push(newHostScene(st))
+	case joinAddr != "":
+		st.push(newJoinScene(st))
+	}
+	st.run()
+}
+
+func loadContent(dir string) error {
+	fsys := content.Builtin()
+	if dir != "" {
+		fsys = os.DirFS(dir)
+	}
+	var err error
+	catalog, err = content.LoadCatalog(fsys)
+	if errors.Is(err, fs.ErrNotExist) && dir != "" {
+		catalog, err = content.LoadCatalog(content.Builtin())
+	}
+	var errs content.ErrorList
+	if catalog == nil || err != nil && !errors.As(err, &errs) {
+		return err
+	}
+	questions, err = content.Load(fsys, catalog)
+	var more content.ErrorList
+	if errors.As(err, &more) {
+		errs = append(errs, more...)
+	} else if err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
 }
 
 func main() {
-	rand.Seed(time.Now().UTC().UnixNano())
+	contentDir := flag.String("content", "", "load the catalog and questions from `dir` instead of the built-in content")
+	configPath := flag.String("config", "", "read settings from `file` instead of config.json in the user config directory")
+	seed := flag.Int64("seed", 0, "seed every session with `n`, so everyone using it gets the same questions in the same order")
+	eventLog := flag.String("events", "", "append a JSON Lines record of every session to `file`")
+	dashboardAddr := flag.String("dashboard", "", "serve a live class dashboard over HTTP on `addr`, such as :8080")
+	flag.StringVar(&hostAddr, "host", "", "host a classroom on `addr`, such as :7070, for students to join")
+	flag.StringVar(&joinAddr, "join", "", "join the classroom hosted at `addr`")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] [lint | confusion | replay file]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			fixedSeed = seed
+		}
+	})
+	switch flag.Arg(0) {
+	case "":
+	case "lint":
+		os.Exit(lint(*contentDir))
+	case "confusion":
+		os.Exit(exportConfusion(*contentDir))
+	case "replay":
+		if flag.NArg() != 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		rec, err := replay.Load(flag.Arg(1))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		startReplay = rec
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := loadContent(*contentDir); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := loadConfig(*configPath); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if startReplay != nil {
+		if err := startReplay.Check(catalog, questions); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", flag.Arg(1), err)
+			os.Exit(1)
+		}
+	}
+	loadPlayer()
+	bus.Subscribe(&recorder{})
+	if *eventLog != "" {
+		log, err := events.OpenJSONL(*eventLog)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		bus.Subscribe(log)
+		defer func() {
+			if err := log.Close(); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			}
+		}()
+	}
+	if *dashboardAddr != "" {
+		board = dashboard.NewBoard(catalog, questions)
+		bus.Subscribe(board)
+		go func() {
+			fmt.Fprintf(os.Stderr, "dashboard on http://%s/\n", *dashboardAddr)
+			if err := http.ListenAndServe(*dashboardAddr, board.Handler()); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			}
+		}()
+	}
 	pixelgl.Run(run)
 
 }
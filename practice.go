@@ -0,0 +1,225 @@
+package main
+
+import (
+	"fmt"
+	"github.com/dkeriazisStuy/FallacyQuest/content"
+	"github.com/dkeriazisStuy/FallacyQuest/input"
+	"github.com/dkeriazisStuy/FallacyQuest/quest"
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+	"image/color"
+	"strings"
+)
+
+const (
+	practiceRows    = 9
+	practiceStep    = 5
+	practiceMax     = 50
+	practiceDefault = 10
+)
+
+// practiceMenu sets up a practice round: which fallacies to drill, picked
+// one by one or by category, and how many questions to ask.
+type practiceMenu struct {
+	st       *sceneStack
+	included map[string]bool
+	total    int
+	boxes    []button
+	presets  []button
+	fewer    button
+	more     button
+	start    button
+	back     button
+	backIcon *imdraw.IMDraw
+	focus    focusList
+	// Text
+	titleTxt, countTxt, poolTxt, startTxt *text.Text
+	fewerTxt, moreTxt                     *text.Text
+	boxTxts, presetTxts                   []*text.Text
+}
+
+// practicePresets are the preset buttons: everything, nothing, then one per
+// category.
+func practicePresets() []string {
+	return append([]string{"all", "none"}, content.Categories...)
+}
+
+func newPracticeMenu(st *sceneStack) *practiceMenu {
+	p := &practiceMenu{st: st, included: make(map[string]bool), total: practiceDefault}
+	p.preset("all")
+	return p
+}
+
+func (p *practiceMenu) enter() {
+	p.titleTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprint(p.titleTxt, "Practice")
+	p.countTxt = text.New(pixel.ZV, atlas)
+	p.poolTxt = text.New(pixel.ZV, atlas)
+	p.startTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprint(p.startTxt, "Start")
+	p.fewerTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprint(p.fewerTxt, "-")
+	p.moreTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprint(p.moreTxt, "+")
+	p.boxTxts = nil
+	for _, f := range catalog.Fallacies {
+		txt := text.New(pixel.ZV, atlas)
+		fmt.Fprint(txt, f.Name)
+		p.boxTxts = append(p.boxTxts, txt)
+	}
+	p.presetTxts = nil
+	for _, name := range practicePresets() {
+		txt := text.New(pixel.ZV, atlas)
+		fmt.Fprint(txt, strings.ToUpper(name[:1])+name[1:])
+		p.presetTxts = append(p.presetTxts, txt)
+	}
+	p.relabel()
+}
+
+func (p *practiceMenu) exit() {}
+
+func (p *practiceMenu) onResize() {
+	win := p.st.win
+	p.back, p.backIcon = backButton(win)
+	presets := practicePresets()
+	p.presets = nil
+	for i := range presets {
+		pos := pixel.V(winX/2+winX*130/origX*(float64(i)-float64(len(presets)-1)/2), winY*640/origY)
+		p.presets = append(p.presets, newButton(win, centeredRect(pos, 60, 18), colornames.Sandybrown, colornames.Rosybrown))
+	}
+	p.boxes = nil
+	for i := range catalog.Fallacies {
+		pos := p.boxPos(i)
+		p.boxes = append(p.boxes, newButton(win, pixel.R(pos.X-winX*15/origX, pos.Y-winY*15/origY, pos.X+winX*400/origX, pos.Y+winY*15/origY), color.Transparent, color.Transparent))
+	}
+	p.fewer = newButton(win, centeredRect(pixel.V(winX/2-winX*160/origX, winY*170/origY), 20, 20), colornames.Sandybrown, colornames.Rosybrown)
+	p.more = newButton(win, centeredRect(pixel.V(winX/2+winX*160/origX, winY*170/origY), 20, 20), colornames.Sandybrown, colornames.Rosybrown)
+	p.start = newButton(win, centeredRect(pixel.V(winX/2, winY*60/origY), 100, 30), colornames.Green, colornames.Darkgreen)
+}
+
+// boxPos is the center of the i'th fallacy's checkbox.
+func (p *practiceMenu) boxPos(i int) pixel.Vec {
+	return pixel.V(winX*(100+440*float64(i/practiceRows))/origX, winY*(570-40*float64(i%practiceRows))/origY)
+}
+
+func (p *practiceMenu) filter() quest.Filter {
+	var f quest.Filter
+	for _, fallacy := range catalog.Fallacies {
+		if p.included[fallacy.Key] {
+			f.Fallacies = append(f.Fallacies, fallacy.Key)
+		}
+	}
+	return f
+}
+
+// pool counts the questions the current selection allows.
+func (p *practiceMenu) pool() int {
+	f := p.filter()
+	if len(f.Fallacies) == 0 {
+		return 0
+	}
+	n := 0
+	for i := range questions {
+		if f.Match(catalog, &questions[i]) {
+			n += 1
+		}
+	}
+	return n
+}
+
+func (p *practiceMenu) preset(name string) {
+	for _, f := range catalog.Fallacies {
+		p.included[f.Key] = name == "all" || f.Category == name
+	}
+}
+
+func (p *practiceMenu) relabel() {
+	p.countTxt.Clear()
+	fmt.Fprintf(p.countTxt, "Questions: %d", p.total)
+	p.poolTxt.Clear()
+	fmt.Fprintf(p.poolTxt, "%d questions to draw from", p.pool())
+}
+
+func (p *practiceMenu) update(dt float64) {
+	var focusable []*button
+	for i := range p.presets {
+		focusable = append(focusable, &p.presets[i])
+	}
+	for i := range p.boxes {
+		focusable = append(focusable, &p.boxes[i])
+	}
+	p.focus.update(append(focusable, &p.fewer, &p.more, &p.start)...)
+	if p.back.check() || in.JustPressed(input.Back) {
+		p.st.pop()
+		return
+	}
+	for i, name := range practicePresets() {
+		if p.presets[i].check() {
+			p.preset(name)
+		}
+	}
+	for i, f := range catalog.Fallacies {
+		if p.boxes[i].check() {
+			p.included[f.Key] = !p.included[f.Key]
+		}
+	}
+	if p.fewer.check() && p.total > practiceStep {
+		p.total -= practiceStep
+	}
+	if p.more.check() && p.total < practiceMax {
+		p.total += practiceStep
+	}
+	p.relabel()
+	if p.start.check() && p.pool() > 0 {
+		p.st.replace(newPracticeScene(p.st, p.filter(), p.total))
+	}
+}
+
+func (p *practiceMenu) draw() {
+	win := p.st.win
+	drawText(win, p.titleTxt, pixel.V(winX/2, winY*715/origY), 4)
+	// Presets
+	for i := range p.presets {
+		p.presets[i].draw()
+		drawText(win, p.presetTxts[i], p.presets[i].rect.Center(), 1.5)
+	}
+	// Fallacies
+	im := imdraw.New(nil)
+	for i, f := range catalog.Fallacies {
+		pos := p.boxPos(i)
+		box := centeredRect(pos, 10, 10)
+		if p.boxes[i].focused {
+			im.Color = colornames.White
+			im.Push(p.boxes[i].rect.Min, p.boxes[i].rect.Max)
+			im.Rectangle(2)
+		}
+		if p.included[f.Key] {
+			im.Color = colornames.Blue
+			im.Push(box.Min, box.Max)
+			im.Rectangle(0)
+		}
+		im.Color = colornames.Gray
+		im.Push(box.Min, box.Max)
+		im.Rectangle(3)
+		txt := p.boxTxts[i]
+		drawText(win, txt, pos.Add(pixel.V(winX*25/origX+txt.Bounds().W()*winX/origX, 0)), 2)
+	}
+	im.Draw(win)
+	// Question count
+	p.fewer.draw()
+	drawText(win, p.fewerTxt, p.fewer.rect.Center(), 3)
+	p.more.draw()
+	drawText(win, p.moreTxt, p.more.rect.Center(), 3)
+	drawText(win, p.countTxt, pixel.V(winX/2, winY*170/origY), 3)
+	drawText(win, p.poolTxt, pixel.V(winX/2, winY*120/origY), 2)
+	// Start
+	if p.pool() > 0 {
+		p.start.draw()
+		drawText(win, p.startTxt, p.start.rect.Center(), 3)
+	}
+	// Back
+	p.back.draw()
+	p.backIcon.Draw(win)
+}
@@ -0,0 +1,355 @@
+// Package profile keeps players' lifetime statistics in JSON save files.
+package profile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/dkeriazisStuy/FallacyQuest/rating"
+	"github.com/dkeriazisStuy/FallacyQuest/srs"
+	"github.com/dkeriazisStuy/FallacyQuest/storage"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Version is the save file schema written by this package.
+const Version = 3
+
+// MaxAttempts is how many of the latest attempts a profile keeps.
+const MaxAttempts = 1000
+
+// Profile is everything remembered about one player.
+type Profile struct {
+	Version   int                   `json:"version"`
+	Name      string                `json:"name"`
+	Created   time.Time             `json:"created"`
+	Stats     Stats                 `json:"stats"`
+	Fallacies map[string]*Accuracy  `json:"fallacies"`
+	History   []Session             `json:"history"`
+	Modes     map[string]*ModeStats `json:"modes"`
+	Study     *srs.Deck             `json:"study,omitempty"`
+	Ratings   *rating.Book          `json:"ratings,omitempty"`
+	// Attempts logs the latest checks of an answer, oldest first.
+	Attempts []Attempt `json:"attempts,omitempty"`
+}
+
+// Stats are totals over every session played.
+type Stats struct {
+	Sessions  int     `json:"sessions"`
+	Questions int     `json:"questions"`
+	Correct   int     `json:"correct"`
+	FirstTry  int     `json:"firstTry"`
+	Skipped   int     `json:"skipped"`
+	BestScore float64 `json:"bestScore"`
+	PlayTime  float64 `json:"playTime"`
+}
+
+// ModeStats are the results of one game mode, kept apart so a long survival
+// run doesn't stand against a ten question round.
+type ModeStats struct {
+	Sessions    int     `json:"sessions"`
+	BestScore   float64 `json:"bestScore"`
+	MostCorrect int     `json:"mostCorrect"`
+}
+
+// Accuracy counts the answers given for one fallacy.
+type Accuracy struct {
+	Seen     int `json:"seen"`
+	Correct  int `json:"correct"`
+	FirstTry int `json:"firstTry"`
+}
+
+// Rate is the share of questions eventually answered correctly.
+func (a *Accuracy) Rate() float64 {
+	if a.Seen == 0 {
+		return 0
+	}
+	return float64(a.Correct) / float64(a.Seen)
+}
+
+// Attempt is one check of an answer to a question about Fallacy. Chosen is
+// the fallacy picked, empty if none was, and Selected the phrase indices.
+type Attempt struct {
+	At       time.Time `json:"at"`
+	Fallacy  string    `json:"fallacy"`
+	Chosen   string    `json:"chosen"`
+	Selected []int     `json:"selected"`
+	Seconds  float64   `json:"seconds"`
+}
+
+// Session summarizes one finished or abandoned session.
+type Session struct {
+	Start     time.Time `json:"start"`
+	Mode      string    `json:"mode"`
+	Score     float64   `json:"score"`
+	Questions int       `json:"questions"`
+	Correct   int       `json:"correct"`
+	Duration  float64   `json:"duration"`
+	Finished  bool      `json:"finished"`
+}
+
+// New returns an empty profile.
+func New(name string) *Profile {
+	return &Profile{
+		Version:   Version,
+		Name:      name,
+		Created:   time.Now(),
+		Fallacies: make(map[string]*Accuracy),
+		Modes:     make(map[string]*ModeStats),
+		Study:     srs.NewDeck(),
+		Ratings:   rating.NewBook(),
+	}
+}
+
+// AddAttempt logs one check of an answer, dropping the oldest beyond
+// MaxAttempts.
+func (p *Profile) AddAttempt(a Attempt) {
+	p.Attempts = append(p.Attempts, a)
+	if over := len(p.Attempts) - MaxAttempts; over > 0 {
+		p.Attempts = p.Attempts[over:]
+	}
+}
+
+// Answer records the outcome of one question about fallacy.
+func (p *Profile) Answer(fallacy string, correct, firstTry bool) {
+	a := p.Fallacies[fallacy]
+	if a == nil {
+		a = &Accuracy{}
+		p.Fallacies[fallacy] = a
+	}
+	a.Seen += 1
+	p.Stats.Questions += 1
+	switch {
+	case correct && firstTry:
+		a.FirstTry += 1
+		p.Stats.FirstTry += 1
+		fallthrough
+	case correct:
+		a.Correct += 1
+		p.Stats.Correct += 1
+	default:
+		p.Stats.Skipped += 1
+	}
+}
+
+// AddSession appends s to the history and updates the totals. The answers
+// themselves are recorded with Answer.
+func (p *Profile) AddSession(s Session) {
+	p.History = append(p.History, s)
+	p.Stats.Sessions += 1
+	p.Stats.PlayTime += s.Duration
+	if s.Finished && s.Score > p.Stats.BestScore {
+		p.Stats.BestScore = s.Score
+	}
+	addMode(p.Modes, s)
+}
+
+func addMode(modes map[string]*ModeStats, s Session) {
+	m := modes[s.Mode]
+	if m == nil {
+		m = &ModeStats{}
+		modes[s.Mode] = m
+	}
+	m.Sessions += 1
+	if s.Finished && s.Score > m.BestScore {
+		m.BestScore = s.Score
+	}
+	if s.Correct > m.MostCorrect {
+		m.MostCorrect = s.Correct
+	}
+}
+
+// Store is a directory of profile save files.
+type Store struct {
+	Dir string
+}
+
+var errNoName = errors.New("profile: name must contain a letter or digit")
+
+// fileName maps a player name onto a safe file name.
+func fileName(name string) (string, error) {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == ' ', r == '-', r == '_':
+			b.WriteRune('_')
+		}
+	}
+	if strings.Trim(b.String(), "_") == "" {
+		return "", errNoName
+	}
+	return b.String() + ".json", nil
+}
+
+// List returns the names of every saved profile.
+func (st Store) List() ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(st.Dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, path := range paths {
+		p, err := read(path)
+		if err != nil {
+			continue
+		}
+		names = append(names, p.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Load reads the profile for name.
+func (st Store) Load(name string) (*Profile, error) {
+	file, err := fileName(name)
+	if err != nil {
+		return nil, err
+	}
+	return read(filepath.Join(st.Dir, file))
+}
+
+// Create makes and saves a new profile, failing if name is taken.
+func (st Store) Create(name string) (*Profile, error) {
+	file, err := fileName(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(filepath.Join(st.Dir, file)); err == nil {
+		return nil, fmt.Errorf("profile: %q already exists", name)
+	}
+	p := New(name)
+	return p, st.Save(p)
+}
+
+// Save writes p to its file.
+func (st Store) Save(p *Profile) error {
+	file, err := fileName(p.Name)
+	if err != nil {
+		return err
+	}
+	p.Version = Version
+	data, err := json.MarshalIndent(p, "", "\t")
+	if err != nil {
+		return err
+	}
+	return storage.WriteFile(filepath.Join(st.Dir, file), data, 0644)
+}
+
+// Last returns the name of the most recently used profile, if any.
+func (st Store) Last() string {
+	data, err := os.ReadFile(filepath.Join(st.Dir, "last"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// SetLast remembers name as the most recently used profile.
+func (st Store) SetLast(name string) error {
+	return storage.WriteFile(filepath.Join(st.Dir, "last"), []byte(name+"\n"), 0644)
+}
+
+func read(path string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if err := migrate(raw); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	data, err = json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	p := New("")
+	p.Study = nil
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if p.Fallacies == nil {
+		p.Fallacies = make(map[string]*Accuracy)
+	}
+	if p.Modes == nil {
+		p.Modes = make(map[string]*ModeStats)
+	}
+	if p.Ratings == nil {
+		p.Ratings = rating.NewBook()
+	}
+	// Saves from before study mode start the deck from their accuracy.
+	if p.Study == nil {
+		p.Study = srs.NewDeck()
+		for name, a := range p.Fallacies {
+			p.Study.Seed(name, a.Seen, a.FirstTry, time.Now())
+		}
+	}
+	return p, nil
+}
+
+// migrations[v] upgrades a decoded save file from version v to v+1. Append
+// one whenever Version is bumped.
+var migrations = map[int]func(raw map[string]interface{}) error{
+	// Version 2 keeps results per mode, rebuilt from the history.
+	1: func(raw map[string]interface{}) error {
+		var history []Session
+		if err := remarshal(raw["history"], &history); err != nil {
+			return err
+		}
+		modes := make(map[string]*ModeStats)
+		for _, s := range history {
+			addMode(modes, s)
+		}
+		var v interface{}
+		if err := remarshal(modes, &v); err != nil {
+			return err
+		}
+		raw["modes"] = v
+		return nil
+	},
+	// Version 3 keeps only the latest MaxAttempts attempts.
+	2: func(raw map[string]interface{}) error {
+		if attempts, ok := raw["attempts"].([]interface{}); ok && len(attempts) > MaxAttempts {
+			raw["attempts"] = attempts[len(attempts)-MaxAttempts:]
+		}
+		return nil
+	},
+}
+
+// remarshal converts between decoded JSON and typed values.
+func remarshal(from, to interface{}) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, to)
+}
+
+func migrate(raw map[string]interface{}) error {
+	v, ok := raw["version"].(float64)
+	if !ok {
+		return errors.New("missing version")
+	}
+	version := int(v)
+	if version > Version {
+		return fmt.Errorf("saved by a newer version of the game (schema %d, want %d)", version, Version)
+	}
+	for ; version < Version; version++ {
+		m := migrations[version]
+		if m == nil {
+			return fmt.Errorf("no migration from schema %d", version)
+		}
+		if err := m(raw); err != nil {
+			return err
+		}
+	}
+	raw["version"] = float64(Version)
+	return nil
+}
@@ -0,0 +1,170 @@
+package profile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCreate(t *testing.T) {
+	st := Store{Dir: t.TempDir()}
+	ann, err := st.Create("Ann Lee")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Names that map onto the same file are taken.
+	for _, name := range []string{"Ann Lee", "ann lee", "ANN_LEE", "Ann-Lee!"} {
+		if _, err := st.Create(name); err == nil {
+			t.Errorf("created %q alongside %q", name, ann.Name)
+		}
+	}
+	for _, name := range []string{"", "  ", "!?", "__"} {
+		if _, err := st.Create(name); err != errNoName {
+			t.Errorf("%q: got %v, want %v", name, err, errNoName)
+		}
+	}
+	if _, err := st.Create("Bob"); err != nil {
+		t.Fatal(err)
+	}
+	names, err := st.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Ann Lee", "Bob"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("List got %v, want %v", names, want)
+	}
+	if got, err := st.Load("ann lee"); err != nil || got.Name != "Ann Lee" {
+		t.Errorf("Load got %+v, %v", got, err)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	st := Store{Dir: t.TempDir()}
+	p, err := st.Create("Ann")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Answer("straw", true, true)
+	p.Answer("straw", true, false)
+	p.Answer("post", false, false)
+	p.AddSession(Session{Mode: "normal", Score: 30, Questions: 3, Correct: 2, Duration: 60, Finished: true})
+	p.AddSession(Session{Mode: "normal", Score: 50, Questions: 3, Correct: 1, Duration: 20})
+	if err := st.Save(p); err != nil {
+		t.Fatal(err)
+	}
+	got, err := st.Load("Ann")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Stats{Sessions: 2, Questions: 3, Correct: 2, FirstTry: 1, Skipped: 1, BestScore: 30, PlayTime: 80}
+	if got.Stats != want {
+		t.Errorf("Stats %+v, want %+v", got.Stats, want)
+	}
+	if a := got.Fallacies["straw"]; a == nil || *a != (Accuracy{Seen: 2, Correct: 2, FirstTry: 1}) {
+		t.Errorf("straw %+v", a)
+	}
+	// An unfinished session doesn't set the best score.
+	if m := got.Modes["normal"]; m == nil || *m != (ModeStats{Sessions: 2, BestScore: 30, MostCorrect: 2}) {
+		t.Errorf("normal %+v", m)
+	}
+
+	if err := st.SetLast("Ann"); err != nil {
+		t.Fatal(err)
+	}
+	if last := st.Last(); last != "Ann" {
+		t.Errorf("Last got %q", last)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	dir := t.TempDir()
+	v1 := `{
+	"version": 1,
+	"name": "Ann",
+	"stats": {"sessions": 3, "questions": 30, "correct": 20},
+	"fallacies": {"straw": {"seen": 4, "correct": 3, "firstTry": 2}},
+	"history": [
+		{"mode": "normal", "score": 40, "questions": 10, "correct": 6, "finished": true},
+		{"mode": "normal", "score": 90, "questions": 10, "correct": 8},
+		{"mode": "survival", "score": 15, "questions": 3, "correct": 2, "finished": true}
+	]
+}`
+	if err := os.WriteFile(filepath.Join(dir, "ann.json"), []byte(v1), 0644); err != nil {
+		t.Fatal(err)
+	}
+	st := Store{Dir: dir}
+	p, err := st.Load("Ann")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Version != Version || p.Stats.Sessions != 3 || len(p.History) != 3 {
+		t.Errorf("got version %d, %+v, %d sessions", p.Version, p.Stats, len(p.History))
+	}
+	want := map[string]*ModeStats{
+		"normal":   {Sessions: 2, BestScore: 40, MostCorrect: 8},
+		"survival": {Sessions: 1, BestScore: 15, MostCorrect: 2},
+	}
+	if !reflect.DeepEqual(p.Modes, want) {
+		t.Errorf("modes rebuilt as %+v", p.Modes)
+	}
+	// Saves from before study mode seed the deck from their accuracy.
+	if p.Study == nil || p.Ratings == nil {
+		t.Errorf("study %v, ratings %v", p.Study, p.Ratings)
+	}
+
+	for name, data := range map[string]string{
+		"newer":      `{"version": 99, "name": "Newer"}`,
+		"no version": `{"name": "No version"}`,
+		"broken":     `{"version": 1,`,
+	} {
+		path := filepath.Join(dir, "bad.json")
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := read(path); err == nil || !strings.Contains(err.Error(), path) {
+			t.Errorf("%s: got %v, want an error naming the file", name, err)
+		}
+	}
+}
+
+func TestAttemptCap(t *testing.T) {
+	p := New("Ann")
+	for i := 0; i < MaxAttempts+5; i++ {
+		p.AddAttempt(Attempt{Fallacy: "straw", Seconds: float64(i)})
+	}
+	if len(p.Attempts) != MaxAttempts || p.Attempts[0].Seconds != 5 {
+		t.Errorf("kept %d attempts from %g on", len(p.Attempts), p.Attempts[0].Seconds)
+	}
+
+	// Saves from before the cap keep only their latest attempts.
+	var b strings.Builder
+	b.WriteString(`{"version": 2, "name": "Ann", "attempts": [`)
+	for i := 0; i < MaxAttempts+5; i++ {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, `{"fallacy": "straw", "chosen": "post", "seconds": %d}`, i)
+	}
+	b.WriteString("]}")
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "ann.json"), []byte(b.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+	st := Store{Dir: dir}
+	p, err := st.Load("Ann")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Attempts) != MaxAttempts || p.Attempts[0].Seconds != 5 || p.Attempts[0].Chosen != "post" {
+		t.Errorf("migrated %d attempts, starting %+v", len(p.Attempts), p.Attempts[0])
+	}
+	if err := st.Save(p); err != nil {
+		t.Fatal(err)
+	}
+	if p, err = st.Load("Ann"); err != nil || p.Version != Version || len(p.Attempts) != MaxAttempts {
+		t.Errorf("after saving: %v", err)
+	}
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"fmt"
+	"github.com/dkeriazisStuy/FallacyQuest/content"
+	"github.com/dkeriazisStuy/FallacyQuest/events"
+	"github.com/dkeriazisStuy/FallacyQuest/input"
+	"github.com/dkeriazisStuy/FallacyQuest/profile"
+	"github.com/dkeriazisStuy/FallacyQuest/quest"
+	"github.com/dkeriazisStuy/FallacyQuest/rating"
+	"github.com/dkeriazisStuy/FallacyQuest/srs"
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	defaultPlayer  = "Player"
+	maxNameLength  = 16
+	profileRows    = 6
+	profileRowSize = 60
+)
+
+var players profile.Store
+
+// player is the active profile, or nil when nothing can be saved.
+var player *profile.Profile
+
+// loadPlayer opens the last used profile, creating a default one on first
+// run. Without a config directory the game runs without saving.
+func loadPlayer() {
+	dir, err := configDir()
+	if err != nil {
+		return
+	}
+	players = profile.Store{Dir: filepath.Join(dir, "profiles")}
+	if p, err := players.Load(players.Last()); err == nil {
+		player = p
+		return
+	}
+	names, _ := players.List()
+	if len(names) > 0 {
+		player, err = players.Load(names[0])
+	} else {
+		player, err = players.Create(defaultPlayer)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+func switchPlayer(p *profile.Profile) {
+	player = p
+	if err := players.SetLast(p.Name); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+// recorder keeps the active profile up to date from the session events.
+// It holds on to a session's results until it ends, so the profile the
+// session draws on doesn't change under it, then saves them. Tutorials
+// aren't recorded.
+type recorder struct {
+	recording bool
+	session   profile.Session
+	attempts  []profile.Attempt
+	answers   []events.Answered
+	byID      map[string]*content.Question
+}
+
+func (r *recorder) Handle(rec events.Record) {
+	if e, ok := rec.Event.(events.SessionStart); ok {
+		*r = recorder{recording: player != nil && e.Mode != modeTutorial, byID: r.byID}
+		r.session = profile.Session{Start: rec.Time, Mode: e.Mode}
+		return
+	}
+	if !r.recording {
+		return
+	}
+	switch e := rec.Event.(type) {
+	case events.Checked:
+		r.attempts = append(r.attempts, profile.Attempt{
+			At:       rec.Time,
+			Fallacy:  e.Fallacy,
+			Chosen:   e.Chosen,
+			Selected: e.Selected,
+			Seconds:  e.Time,
+		})
+	case events.Answered:
+		r.answers = append(r.answers, e)
+	case events.SessionEnd:
+		r.recording = false
+		if len(r.answers) == 0 {
+			return
+		}
+		r.session.Score = e.Score
+		r.session.Duration = e.Elapsed
+		r.session.Finished = e.Finished
+		r.save()
+	}
+}
+
+// save adds the session's results to the active profile and saves it.
+func (r *recorder) save() {
+	now := time.Now()
+	for _, a := range r.answers {
+		player.Answer(a.Fallacy, a.Correct, a.Correct && a.Checks == 1)
+		if q := r.question(a.Question); q != nil {
+			player.Study.Record(q, srs.Quality(a.Correct, a.Checks, a.Credit), now)
+			player.Ratings.Update(q, rating.Outcome(a.Correct, a.Checks, a.Credit, a.Time))
+		}
+		r.session.Questions += 1
+		if a.Correct {
+			r.session.Correct += 1
+		}
+	}
+	for _, a := range r.attempts {
+		player.AddAttempt(a)
+	}
+	player.AddSession(r.session)
+	if err := players.Save(player); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+// question finds a question in the bank by ID.
+func (r *recorder) question(id string) *content.Question {
+	if r.byID == nil {
+		r.byID = make(map[string]*content.Question)
+		for i := range questions {
+			r.byID[questions[i].ID()] = &questions[i]
+		}
+	}
+	return r.byID[id]
+}
+
+// deckPicker serves the questions the player's study deck says are due,
+// never repeating one within a session while others remain.
+type deckPicker struct {
+	deck *srs.Deck
+}
+
+func (p deckPicker) Pick(s *quest.Session) *content.Question {
+	asked := make(map[string]bool)
+	for _, a := range s.Answers {
+		asked[a.Question.ID()] = true
+	}
+	return p.deck.Next(s.Questions, asked, time.Now(), s.Rand)
+}
+
+// studyPicker schedules from the active profile, or picks at random when
+// nothing is saved.
+func studyPicker() quest.Picker {
+	if player == nil {
+		return nil
+	}
+	return deckPicker{player.Study}
+}
+
+// adaptivePicker serves questions rated to suit the player. It rates the
+// session's answers as they come on a copy of the profile's ratings, which
+// recorder.save updates for real at the end.
+type adaptivePicker struct {
+	book  *rating.Book
+	rated int
+}
+
+func (p *adaptivePicker) Pick(s *quest.Session) *content.Question {
+	asked := make(map[string]bool)
+	for _, a := range s.Answers {
+		asked[a.Question.ID()] = true
+	}
+	for ; p.rated < len(s.Answers); p.rated++ {
+		a := s.Answers[p.rated]
+		p.book.Update(a.Question, rating.Outcome(a.Correct, a.Checks, a.Credit, a.Time))
+	}
+	return p.book.Next(s.Questions, asked, s.Rand)
+}
+
+type profileScene struct {
+	st       *sceneStack
+	names    []string
+	rows     []button
+	rowTxts  []*text.Text
+	pager    pager
+	input    string
+	back     button
+	backIcon *imdraw.IMDraw
+	create   button
+	focus    focusList
+	// Text
+	titleTxt, inputTxt, createTxt, errTxt *text.Text
+}
+
+func newProfileScene(st *sceneStack) *profileScene {
+	return &profileScene{st: st}
+}
+
+func (p *profileScene) enter() {
+	p.names, _ = players.List()
+	p.pager = newPager(profileRows, len(p.names))
+	p.rowTxts = nil
+	for i, name := range p.names {
+		txt := text.New(pixel.ZV, atlas)
+		if player != nil && name == player.Name {
+			txt.Color = colornames.Darkblue
+			p.pager.page = i / profileRows
+		}
+		fmt.Fprint(txt, name)
+		p.rowTxts = append(p.rowTxts, txt)
+	}
+	p.titleTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprint(p.titleTxt, "Choose a profile")
+	p.inputTxt = text.New(pixel.ZV, atlas)
+	p.createTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprint(p.createTxt, "Create")
+	p.errTxt = text.New(pixel.ZV, atlas)
+	p.errTxt.Color = colornames.Yellow
+}
+
+func (p *profileScene) exit() {}
+
+func (p *profileScene) onResize() {
+	win := p.st.win
+	p.back, p.backIcon = backButton(win)
+	p.layoutRows()
+	p.pager.onResize(win, 3*winY/4)
+	p.create = newButton(win, centeredRect(pixel.V(winX/2+winX*200/origX, winY/6), 75, 25), colornames.Green, colornames.Darkgreen)
+}
+
+func (p *profileScene) update(dt float64) {
+	for _, r := range in.Text {
+		if r >= ' ' && r <= '~' && len(p.input) < maxNameLength {
+			p.input += string(r)
+		}
+	}
+	if in.JustPressed(input.Erase) && len(p.input) > 0 {
+		p.input = p.input[:len(p.input)-1]
+	}
+	var focusable []*button
+	for i := range p.rows {
+		focusable = append(focusable, &p.rows[i])
+	}
+	p.focus.update(append(append(focusable, p.pager.buttons()...), &p.create)...)
+	if p.back.check() || in.JustPressed(input.Back) {
+		p.st.pop()
+		return
+	}
+	if p.pager.update() {
+		p.layoutRows()
+		return
+	}
+	from, _ := p.pager.span()
+	for i := range p.rows {
+		if p.rows[i].check() {
+			if prof, err := players.Load(p.names[from+i]); err != nil {
+				p.fail(err)
+			} else {
+				switchPlayer(prof)
+				p.st.pop()
+			}
+			return
+		}
+	}
+	if p.create.check() || in.JustPressed(input.Confirm) {
+		if prof, err := players.Create(p.input); err != nil {
+			p.fail(err)
+		} else {
+			switchPlayer(prof)
+			p.st.pop()
+		}
+	}
+}
+
+// layoutRows makes a button for each name on the current page.
+func (p *profileScene) layoutRows() {
+	p.rows = nil
+	from, to := p.pager.span()
+	for i := range p.names[from:to] {
+		pos := pixel.V(winX/2, 3*winY/4-float64(i)*winY*profileRowSize/origY)
+		p.rows = append(p.rows, newButton(p.st.win, centeredRect(pos, 150, 25), colornames.Sandybrown, colornames.Rosybrown))
+	}
+}
+
+func (p *profileScene) fail(err error) {
+	p.errTxt.Clear()
+	fmt.Fprint(p.errTxt, err)
+}
+
+func (p *profileScene) draw() {
+	win := p.st.win
+	drawText(win, p.titleTxt, pixel.V(winX/2, 7*winY/8), 4)
+	from, _ := p.pager.span()
+	for i := range p.rows {
+		p.rows[i].draw()
+		drawText(win, p.rowTxts[from+i], p.rows[i].rect.Center(), 2)
+	}
+	p.pager.draw(win)
+	// Name input
+	inputPos := pixel.V(winX/2-winX*50/origX, winY/6)
+	inputRect := centeredRect(inputPos, 200, 25)
+	box := imdraw.New(nil)
+	box.Color = colornames.White
+	box.Push(inputRect.Min, inputRect.Max)
+	box.Rectangle(0)
+	box.Draw(win)
+	p.inputTxt.Clear()
+	p.inputTxt.Color = colornames.Black
+	fmt.Fprint(p.inputTxt, p.input+"_")
+	drawText(win, p.inputTxt, inputPos, 2)
+	p.create.draw()
+	drawText(win, p.createTxt, p.create.rect.Center(), 2)
+	drawText(win, p.errTxt, pixel.V(winX/2, winY/12), 1.5)
+	// Back
+	p.back.draw()
+	p.backIcon.Draw(win)
+}
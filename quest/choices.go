@@ -0,0 +1,59 @@
+package quest
+
+import (
+	"github.com/dkeriazisStuy/FallacyQuest/content"
+	"math/rand"
+)
+
+func shuffle(rng *rand.Rand, fallacySlice *[]string) {
+	for i := range *fallacySlice {
+		j := rng.Intn(i + 1)
+		(*fallacySlice)[i], (*fallacySlice)[j] = (*fallacySlice)[j], (*fallacySlice)[i]
+	}
+}
+
+// A Distractor chooses the fallacies offered alongside the answer, for
+// rounds that must offer the choices they were dealt elsewhere. Choices
+// returns the answer and the distractors, shuffled, with no repeats.
+type Distractor interface {
+	Choices(rng *rand.Rand, cat *content.Catalog, answer string) []string
+}
+
+func contains(list []string, key string) bool {
+	for _, k := range list {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// add appends up to n entries of pool that aren't already in result.
+func add(result, pool []string, n int) []string {
+	for _, key := range pool {
+		if n <= 0 {
+			break
+		}
+		if !contains(result, key) {
+			result = append(result, key)
+			n -= 1
+		}
+	}
+	return result
+}
+
+// FallacyChoices returns name, two of its related fallacies and one other
+// fallacy, shuffled. Other fallacies fill in when name has too few related.
+func FallacyChoices(rng *rand.Rand, cat *content.Catalog, name string) []string {
+	var related []string
+	if f := cat.Get(name); f != nil {
+		related = append(related, f.Related...)
+	}
+	shuffle(rng, &related)
+	fallacyKeys := cat.Keys()
+	shuffle(rng, &fallacyKeys)
+	result := add([]string{name}, related, 2)
+	result = add(result, fallacyKeys, 4-len(result))
+	shuffle(rng, &result)
+	return result
+}
@@ -0,0 +1,77 @@
+package quest
+
+import (
+	"github.com/dkeriazisStuy/FallacyQuest/content"
+	"math/rand"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFallacyChoices(t *testing.T) {
+	cat, _ := fixture(t)
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		for _, answer := range cat.Keys() {
+			choices := FallacyChoices(rng, cat, answer)
+			seen := make(map[string]bool)
+			related := 0
+			for _, c := range choices {
+				if seen[c] {
+					t.Fatalf("%q offered twice in %v", c, choices)
+				}
+				if cat.Get(c) == nil {
+					t.Fatalf("%q is not in the catalog", c)
+				}
+				if contains(cat.Get(answer).Related, c) {
+					related += 1
+				}
+				seen[c] = true
+			}
+			if !seen[answer] || len(choices) != 4 || related < 2 {
+				t.Fatalf("got %v for %q, want the answer, two related and one other", choices, answer)
+			}
+		}
+	}
+}
+
+func TestFallacyChoicesFewRelated(t *testing.T) {
+	fsys := fstest.MapFS{"catalog.json": {Data: []byte(`{
+  "version": 1,
+  "fallacies": [
+    {"key": "a", "name": "A", "args": 1, "roles": ["r"], "related": ["b"]},
+    {"key": "b", "name": "B", "args": 1, "roles": ["r"]},
+    {"key": "c", "name": "C", "args": 1, "roles": ["r"]}
+  ]
+}`)}}
+	cat, err := content.LoadCatalog(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rng := rand.New(rand.NewSource(1))
+	for _, answer := range []string{"a", "b", "c", "unknown"} {
+		choices := FallacyChoices(rng, cat, answer)
+		if len(choices) < 2 || !contains(choices, answer) {
+			t.Errorf("got %v for %q", choices, answer)
+		}
+	}
+	if choices := FallacyChoices(rng, cat, "a"); len(choices) != 3 || !contains(choices, "b") {
+		t.Errorf("got %v for a, want all three with its related b", choices)
+	}
+}
+
+func TestItemCheck(t *testing.T) {
+	cat, _ := fixture(t)
+	for _, tt := range []struct {
+		item Item
+		ok   bool
+	}{
+		{Item{Fallacy: "straw", Choices: []string{"hominem", "straw"}}, true},
+		{Item{Fallacy: "straw", Choices: []string{"hominem", "emotion"}}, false},
+		{Item{Fallacy: "straw", Choices: []string{"straw", "nope"}}, false},
+		{Item{Fallacy: "straw"}, false},
+	} {
+		if err := tt.item.Check(cat); (err == nil) != tt.ok {
+			t.Errorf("%+v: got %v", tt.item, err)
+		}
+	}
+}
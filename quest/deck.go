@@ -0,0 +1,209 @@
+package quest
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dkeriazisStuy/FallacyQuest/content"
+	"math/rand"
+)
+
+// Filter narrows the questions a deck deals. Zero values match everything.
+type Filter struct {
+	Fallacies     []string
+	Categories    []string
+	MinDifficulty int
+	MaxDifficulty int
+}
+
+// Match reports whether q passes the filter.
+func (f Filter) Match(cat *content.Catalog, q *content.Question) bool {
+	if len(f.Fallacies) > 0 && !contains(f.Fallacies, q.Name) {
+		return false
+	}
+	if len(f.Categories) > 0 {
+		found := false
+		for _, c := range f.Categories {
+			if fallacy := cat.Get(q.Name); fallacy != nil && fallacy.Category == c {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	level := q.Level()
+	return (f.MinDifficulty == 0 || level >= f.MinDifficulty) && (f.MaxDifficulty == 0 || level <= f.MaxDifficulty)
+}
+
+// Deck deals questions in a shuffled order without repeats. Once every
+// question has been dealt it reshuffles and starts over.
+type Deck struct {
+	rng       *rand.Rand
+	questions []*content.Question
+	next      int
+}
+
+// NewDeck shuffles the questions that pass filter with rng, which is
+// usually shared with the session the deck deals for.
+func NewDeck(cat *content.Catalog, questions []content.Question, filter Filter, rng *rand.Rand) (*Deck, error) {
+	d := &Deck{rng: rng}
+	for i := range questions {
+		if filter.Match(cat, &questions[i]) {
+			d.questions = append(d.questions, &questions[i])
+		}
+	}
+	if len(d.questions) == 0 {
+		return nil, errors.New("quest: no questions match the filter")
+	}
+	d.shuffle()
+	return d, nil
+}
+
+func (d *Deck) shuffle() {
+	d.rng.Shuffle(len(d.questions), func(i, j int) {
+		d.questions[i], d.questions[j] = d.questions[j], d.questions[i]
+	})
+	d.next = 0
+}
+
+// Len is the number of questions in the deck.
+func (d *Deck) Len() int {
+	return len(d.questions)
+}
+
+// Deal returns the next question.
+func (d *Deck) Deal() *content.Question {
+	if d.next == len(d.questions) {
+		last := d.questions[d.next-1]
+		d.shuffle()
+		// Don't deal the same question twice in a row across a reshuffle.
+		if len(d.questions) > 1 && d.questions[0] == last {
+			j := 1 + d.rng.Intn(len(d.questions)-1)
+			d.questions[0], d.questions[j] = d.questions[j], d.questions[0]
+		}
+	}
+	d.next += 1
+	return d.questions[d.next-1]
+}
+
+// Pick deals the next question, so a deck can drive a session.
+func (d *Deck) Pick(s *Session) *content.Question {
+	return d.Deal()
+}
+
+// Ramp deals harder questions as the combo grows, moving up a level every
+// Step correct answers in a row and back to the easiest on a mistake.
+type Ramp struct {
+	Decks []*Deck
+	Step  int
+}
+
+// NewRamp makes a deck for each difficulty level that has questions.
+func NewRamp(cat *content.Catalog, questions []content.Question, step int, rng *rand.Rand) (*Ramp, error) {
+	r := &Ramp{Step: step}
+	for level := content.Easy; level <= content.Hard; level++ {
+		d, err := NewDeck(cat, questions, Filter{MinDifficulty: level, MaxDifficulty: level}, rng)
+		if err == nil {
+			r.Decks = append(r.Decks, d)
+		}
+	}
+	if len(r.Decks) == 0 {
+		return nil, errors.New("quest: no questions to ramp through")
+	}
+	return r, nil
+}
+
+func (r *Ramp) Pick(s *Session) *content.Question {
+	level := len(r.Decks) - 1
+	if r.Step > 0 && s.Combo/r.Step < level {
+		level = s.Combo / r.Step
+	}
+	return r.Decks[level].Deal()
+}
+
+// Sequence asks set questions with set choices, in order, for rounds that
+// must match one played elsewhere. It is both the Picker and the Distractor
+// of a session.
+type Sequence struct {
+	Items []Item
+	next  int
+}
+
+// Item is one question of a Sequence, by ID, with its answer and the
+// choices to offer.
+type Item struct {
+	Question string   `json:"question"`
+	Fallacy  string   `json:"fallacy"`
+	Choices  []string `json:"choices"`
+}
+
+// Check reports whether the item can be offered with cat: its answer must
+// be among its choices and every choice in the catalog.
+func (it Item) Check(cat *content.Catalog) error {
+	if !contains(it.Choices, it.Fallacy) {
+		return fmt.Errorf("doesn't offer its answer %q", it.Fallacy)
+	}
+	for _, key := range it.Choices {
+		if cat.Get(key) == nil {
+			return fmt.Errorf("offers fallacy %q, which is not in the catalog", key)
+		}
+	}
+	return nil
+}
+
+// Check reports whether the sequence can be played with cat and questions:
+// every item must name a question in the bank, with the same answer, and
+// pass Item.Check.
+func (q *Sequence) Check(cat *content.Catalog, questions []content.Question) error {
+	byID := make(map[string]*content.Question, len(questions))
+	for i := range questions {
+		byID[questions[i].ID()] = &questions[i]
+	}
+	for i, item := range q.Items {
+		question := byID[item.Question]
+		switch {
+		case question == nil:
+			return fmt.Errorf("question %d (%s) is not in the question bank", i+1, item.Question)
+		case question.Name != item.Fallacy:
+			return fmt.Errorf("question %d is %q in the question bank, not %q", i+1, question.Name, item.Fallacy)
+		}
+		if err := item.Check(cat); err != nil {
+			return fmt.Errorf("question %d %v", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (q *Sequence) Pick(s *Session) *content.Question {
+	if q.next >= len(q.Items) {
+		return nil
+	}
+	for i := range s.Questions {
+		if s.Questions[i].ID() == q.Items[q.next].Question {
+			return &s.Questions[i]
+		}
+	}
+	return nil
+}
+
+// Choices offers the set choices that are in the catalog, then moves on to
+// the next item. Past the end of the sequence, or if the answer differs
+// because the question bank has changed, it offers just the answer.
+func (q *Sequence) Choices(rng *rand.Rand, cat *content.Catalog, answer string) []string {
+	if q.next >= len(q.Items) {
+		return []string{answer}
+	}
+	item := q.Items[q.next]
+	q.next += 1
+	if item.Fallacy != answer {
+		return []string{answer}
+	}
+	var choices []string
+	for _, key := range item.Choices {
+		if cat.Get(key) != nil {
+			choices = append(choices, key)
+		}
+	}
+	return choices
+}
@@ -0,0 +1,67 @@
+package quest
+
+// fallacyWeight is the share of credit for naming the right fallacy. The
+// rest comes from how well the selected phrases match the answer, and only
+// counts once the fallacy is right, so guessing earns nothing.
+const fallacyWeight = .5
+
+// Mark tells how one phrase or choice fared in the last check.
+type Mark int
+
+const (
+	Unmarked Mark = iota
+	Hit           // selected and right
+	Wrong         // selected but wrong
+	Missed        // right but not selected
+)
+
+// Result grades one check of an answer.
+type Result struct {
+	Fallacy bool // the chosen fallacy is right
+	Hits    int  // answer phrases that were selected
+	Extra   int  // selected phrases that are not answers
+	Missed  int  // answer phrases that were not selected
+
+	Precision float64
+	Recall    float64
+	Credit    float64
+	Correct   bool
+}
+
+func grade(q answerKey, chosen string, selected []int) Result {
+	r := Result{Fallacy: chosen == q.name}
+	isAns := make(map[int]bool)
+	for _, a := range q.ans {
+		isAns[a] = true
+	}
+	for _, v := range selected {
+		if isAns[v] {
+			r.Hits += 1
+		} else {
+			r.Extra += 1
+		}
+	}
+	r.Missed = len(isAns) - r.Hits
+	if len(selected) > 0 {
+		r.Precision = float64(r.Hits) / float64(len(selected))
+	}
+	if len(isAns) > 0 {
+		r.Recall = float64(r.Hits) / float64(len(isAns))
+	}
+	f1 := 0.0
+	if r.Precision+r.Recall > 0 {
+		f1 = 2 * r.Precision * r.Recall / (r.Precision + r.Recall)
+	}
+	r.Correct = r.Fallacy && r.Extra == 0 && r.Missed == 0
+	if r.Correct {
+		r.Credit = 1
+	} else if r.Fallacy {
+		r.Credit = fallacyWeight + (1-fallacyWeight)*f1
+	}
+	return r
+}
+
+type answerKey struct {
+	name string
+	ans  []int
+}
@@ -0,0 +1,354 @@
+// Package quest holds the rules of a Fallacy Quest session, independent of
+// any window or input device.
+package quest
+
+import (
+	"github.com/dkeriazisStuy/FallacyQuest/content"
+	"github.com/dkeriazisStuy/FallacyQuest/events"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Session is one round of questions along with the player's score. A round
+// ends after Total questions, once TimeLimit seconds have passed, or when
+// every one of Lives is lost to a wrong check or a skip; zero means no limit.
+type Session struct {
+	Catalog    *content.Catalog
+	Questions  []content.Question
+	Total      int
+	TimeLimit  float64
+	Lives      int
+	Scorer     Scorer
+	Picker     Picker
+	Distractor Distractor
+	// Rand makes every random choice in the session, so sessions with the
+	// same seed play out the same.
+	Rand *rand.Rand
+	// Events hears about every question shown and every move the player
+	// makes. It may be nil.
+	Events *events.Bus
+
+	Score   float64
+	Combo   int
+	Count   int
+	Lost    int
+	Timer   float64
+	Elapsed float64
+	Done    bool
+	Answers []Answer
+
+	Question *content.Question
+	Choices  []string
+	Chosen   int
+	Selected []bool
+	Correct  bool
+	Checks   int
+	Last     Result
+	Credit   float64
+
+	// Marks grade each phrase and ChoiceMark the chosen fallacy as of the
+	// last check. A mark is cleared when the player changes that part of
+	// the answer.
+	Marks      []Mark
+	ChoiceMark Mark
+
+	// Gain is the offer for the current question and Points what the best
+	// answer to it so far earned.
+	Gain   float64
+	Points float64
+}
+
+// A Picker chooses the next question of a session.
+type Picker interface {
+	Pick(s *Session) *content.Question
+}
+
+// Answer is the outcome of one question the player moved on from.
+type Answer struct {
+	Question *content.Question
+	Correct  bool
+	Checks   int
+	Credit   float64
+	Points   float64
+	Time     float64
+}
+
+// Options are the optional parts of a session. A nil Scorer means the
+// classic scoring, a nil Picker picks at random, a nil Distractor offers
+// FallacyChoices and a nil Rand is seeded from the clock. Events may
+// be nil.
+type Options struct {
+	Scorer     Scorer
+	Picker     Picker
+	Distractor Distractor
+	Rand       *rand.Rand
+	Events     *events.Bus
+	TimeLimit  float64
+	Lives      int
+}
+
+// NewSession starts a round of total questions drawn from questions.
+func NewSession(cat *content.Catalog, questions []content.Question, total int, opts Options) *Session {
+	if opts.Scorer == nil {
+		classic := DefaultScoring().Classic
+		opts.Scorer = &classic
+	}
+	if opts.Rand == nil {
+		opts.Rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	s := &Session{
+		Catalog:    cat,
+		Questions:  questions,
+		Total:      total,
+		TimeLimit:  opts.TimeLimit,
+		Lives:      opts.Lives,
+		Scorer:     opts.Scorer,
+		Picker:     opts.Picker,
+		Distractor: opts.Distractor,
+		Rand:       opts.Rand,
+		Events:     opts.Events,
+		Count:      1,
+	}
+	s.next()
+	return s
+}
+
+func (s *Session) next() {
+	s.Question = nil
+	if s.Picker != nil {
+		s.Question = s.Picker.Pick(s)
+	}
+	if s.Question == nil {
+		s.Question = &s.Questions[s.Rand.Intn(len(s.Questions))]
+	}
+	if s.Distractor != nil {
+		s.Choices = s.Distractor.Choices(s.Rand, s.Catalog, s.Question.Name)
+	} else {
+		s.Choices = FallacyChoices(s.Rand, s.Catalog, s.Question.Name)
+	}
+	s.Chosen = -1
+	s.Selected = make([]bool, len(s.Question.Phrases))
+	s.Marks = make([]Mark, len(s.Question.Phrases))
+	s.ChoiceMark = Unmarked
+	s.Correct = false
+	s.Checks = 0
+	s.Last = Result{}
+	s.Credit = 0
+	s.Timer = 0
+	s.Points = 0
+	s.Scorer.Begin(s)
+	s.Events.Emit(events.QuestionShown{
+		Number:   s.Count,
+		Question: s.Question.ID(),
+		Fallacy:  s.Question.Name,
+		Choices:  s.Choices,
+	})
+}
+
+func (s *Session) locked() bool {
+	return s.Done || s.Correct
+}
+
+// SelectChoice picks the fallacy at index i of Choices.
+func (s *Session) SelectChoice(i int) {
+	if s.locked() || i < 0 || i >= len(s.Choices) {
+		return
+	}
+	if i != s.Chosen {
+		s.ChoiceMark = Unmarked
+	}
+	s.Chosen = i
+	s.Events.Emit(events.ChoiceSelected{Choice: i, Fallacy: s.Choices[i]})
+}
+
+// TogglePhrase selects or deselects phrase i of the current question.
+func (s *Session) TogglePhrase(i int) {
+	if s.locked() || i < 0 || i >= len(s.Selected) {
+		return
+	}
+	s.Selected[i] = !s.Selected[i]
+	s.Marks[i] = Unmarked
+	s.Events.Emit(events.PhraseToggled{Phrase: i, Selected: s.Selected[i]})
+}
+
+// SelectedPhrases returns the indices of the selected phrases.
+func (s *Session) SelectedPhrases() []int {
+	var selected []int
+	for i, v := range s.Selected {
+		if v {
+			selected = append(selected, i)
+		}
+	}
+	return selected
+}
+
+// Check grades the current answer. A wrong answer breaks the combo and is
+// penalized by the scorer, but the player may try again. Partly right
+// answers earn their share of the points, which are banked on Skip.
+func (s *Session) Check() Result {
+	if s.locked() {
+		return s.Last
+	}
+	chosen := ""
+	if s.Chosen >= 0 {
+		chosen = s.Choices[s.Chosen]
+	}
+	selected := s.SelectedPhrases()
+	timer := s.Timer
+	s.Last = grade(answerKey{s.Question.Name, s.Question.Ans}, chosen, selected)
+	s.Checks += 1
+	s.Correct = s.Last.Correct
+	s.mark()
+	if s.Last.Credit > s.Credit {
+		s.Credit = s.Last.Credit
+		best := s.Points
+		s.Scorer.Correct(s)
+		s.Points = math.Max(best, s.Points*s.Credit)
+	}
+	if !s.Correct {
+		s.Combo = 0
+		s.Scorer.Wrong(s)
+	}
+	s.Events.Emit(events.Checked{
+		Question: s.Question.ID(),
+		Fallacy:  s.Question.Name,
+		Chosen:   chosen,
+		Selected: selected,
+		Correct:  s.Last.Correct,
+		Credit:   s.Last.Credit,
+		Time:     timer,
+		Elapsed:  s.Elapsed,
+		Score:    s.Score,
+	})
+	if !s.Correct && s.loseLife() {
+		s.bank()
+		s.Done = true
+	}
+	return s.Last
+}
+
+func (s *Session) mark() {
+	isAns := make(map[int]bool)
+	for _, a := range s.Question.Ans {
+		isAns[a] = true
+	}
+	for i, selected := range s.Selected {
+		switch {
+		case selected && isAns[i]:
+			s.Marks[i] = Hit
+		case selected:
+			s.Marks[i] = Wrong
+		case isAns[i]:
+			s.Marks[i] = Missed
+		default:
+			s.Marks[i] = Unmarked
+		}
+	}
+	switch {
+	case s.Chosen < 0:
+		s.ChoiceMark = Unmarked
+	case s.Last.Fallacy:
+		s.ChoiceMark = Hit
+	default:
+		s.ChoiceMark = Wrong
+	}
+}
+
+// Skip moves on to the next question, banking the points earned on the
+// current one. Unless it was answered correctly, the combo is broken, the
+// scorer's penalty applies and a life is lost.
+func (s *Session) Skip() {
+	if s.Done {
+		return
+	}
+	s.Events.Emit(events.Skipped{Question: s.Question.ID(), Correct: s.Correct})
+	if s.Correct {
+		s.Combo += 1
+	} else {
+		s.Combo = 0
+		s.Scorer.Skip(s)
+	}
+	s.bank()
+	s.Count += 1
+	if !s.Correct && s.loseLife() || s.Total > 0 && s.Count > s.Total {
+		s.Done = true
+		return
+	}
+	s.next()
+}
+
+// bank records the answer to the current question and adds its points.
+func (s *Session) bank() {
+	s.Answers = append(s.Answers, Answer{
+		Question: s.Question,
+		Correct:  s.Correct,
+		Checks:   s.Checks,
+		Credit:   s.Credit,
+		Points:   s.Points,
+		Time:     s.Timer,
+	})
+	s.Score += s.Points
+	s.Events.Emit(events.Answered{
+		Question: s.Question.ID(),
+		Fallacy:  s.Question.Name,
+		Correct:  s.Correct,
+		Checks:   s.Checks,
+		Credit:   s.Credit,
+		Points:   s.Points,
+		Time:     s.Timer,
+		Score:    s.Score,
+	})
+}
+
+// loseLife takes a life, reporting whether that was the last one.
+func (s *Session) loseLife() bool {
+	if s.Lives == 0 {
+		return false
+	}
+	s.Lost += 1
+	return s.Lost >= s.Lives
+}
+
+// LivesLeft is how many lives remain, or 0 without a limit.
+func (s *Session) LivesLeft() int {
+	return s.Lives - s.Lost
+}
+
+// Tick advances the question and session timers by dt seconds. When the
+// time limit runs out the session ends; a question already answered right
+// is banked, anything else is dropped.
+func (s *Session) Tick(dt float64) {
+	if s.Done {
+		return
+	}
+	s.Timer += dt
+	s.Elapsed += dt
+	if s.TimeLimit > 0 && s.Elapsed >= s.TimeLimit {
+		s.Elapsed = s.TimeLimit
+		s.Done = true
+		if s.Correct {
+			s.Combo += 1
+			s.bank()
+		}
+	}
+}
+
+// Remaining is the time left before the limit, or 0 without one.
+func (s *Session) Remaining() float64 {
+	return math.Max(s.TimeLimit-s.Elapsed, 0)
+}
+
+// Accuracy is the share of answered questions that were answered right.
+func (s *Session) Accuracy() float64 {
+	if len(s.Answers) == 0 {
+		return 0
+	}
+	correct := 0
+	for _, a := range s.Answers {
+		if a.Correct {
+			correct += 1
+		}
+	}
+	return float64(correct) / float64(len(s.Answers))
+}
@@ -0,0 +1,330 @@
+package quest
+
+import (
+	"github.com/dkeriazisStuy/FallacyQuest/content"
+	"github.com/dkeriazisStuy/FallacyQuest/events"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+const testCatalog = `{
+  "version": 1,
+  "fallacies": [
+    {"key": "hominem", "name": "Ad Hominem", "args": 1, "roles": ["the attack"], "related": ["straw", "emotion"]},
+    {"key": "straw", "name": "Straw Man", "args": 2, "roles": ["the argument", "the distortion"], "related": ["hominem", "emotion"]},
+    {"key": "emotion", "name": "Appeal to Emotion", "args": 1, "roles": ["the appeal"], "related": ["hominem", "straw"]},
+    {"key": "cause", "name": "False Cause", "args": 2, "roles": ["the cause", "the effect"], "related": ["slope", "chance"]},
+    {"key": "slope", "name": "Slippery Slope", "args": 1, "roles": ["the slope"], "related": ["cause", "chance"]},
+    {"key": "chance", "name": "Gambler's Fallacy", "args": 1, "roles": ["the bet"], "related": ["cause", "slope"]}
+  ]
+}`
+
+const testQuestions = `[
+  {"name": "hominem", "phrases": ["Don't listen", "to him.", "He is a fool."], "ans": [2]},
+  {"name": "straw", "phrases": ["She wants", "less traffic.", "So she wants", "to ban cars."], "ans": [1, 3]},
+  {"name": "emotion", "phrases": ["Think of", "the children!"], "ans": [1]},
+  {"name": "cause", "phrases": ["I wore socks", "and then", "it rained."], "ans": [0, 2]},
+  {"name": "slope", "phrases": ["If we allow this,", "then anything goes."], "ans": [1]},
+  {"name": "chance", "phrases": ["Red came up", "five times,", "so black is due."], "ans": [2]}
+]`
+
+// fixture loads a small catalog and question bank.
+func fixture(t *testing.T) (*content.Catalog, []content.Question) {
+	t.Helper()
+	fsys := fstest.MapFS{
+		"catalog.json":        {Data: []byte(testCatalog)},
+		"questions/test.json": {Data: []byte(testQuestions)},
+	}
+	cat, err := content.LoadCatalog(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	questions, err := content.Load(fsys, cat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cat, questions
+}
+
+// answer selects the right fallacy and phrases for the current question.
+func answer(s *Session) {
+	for i, c := range s.Choices {
+		if c == s.Question.Name {
+			s.SelectChoice(i)
+		}
+	}
+	for _, a := range s.Question.Ans {
+		s.TogglePhrase(a)
+	}
+}
+
+// miss selects a wrong fallacy and no phrases.
+func miss(s *Session) {
+	for i, c := range s.Choices {
+		if c != s.Question.Name {
+			s.SelectChoice(i)
+			return
+		}
+	}
+}
+
+func newTestSession(t *testing.T, total int, opts Options) *Session {
+	t.Helper()
+	cat, questions := fixture(t)
+	if opts.Rand == nil {
+		opts.Rand = rand.New(rand.NewSource(1))
+	}
+	return NewSession(cat, questions, total, opts)
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestSessionRound(t *testing.T) {
+	s := newTestSession(t, 3, Options{})
+	for i := 0; i < 3; i++ {
+		if s.Done || s.Count != i+1 {
+			t.Fatalf("question %d: Done %v, Count %d", i+1, s.Done, s.Count)
+		}
+		answer(s)
+		if r := s.Check(); !r.Correct || r.Credit != 1 {
+			t.Fatalf("question %d: got %+v, want correct", i+1, r)
+		}
+		s.Skip()
+	}
+	if !s.Done {
+		t.Fatal("session not done after 3 questions")
+	}
+	if s.Combo != 3 || len(s.Answers) != 3 || s.Accuracy() != 1 {
+		t.Errorf("Combo %d, %d answers, accuracy %g; want 3, 3, 1", s.Combo, len(s.Answers), s.Accuracy())
+	}
+	// Classic scoring with no time taken: 10, then 10+1*10/2 = 15, then
+	// 10+2*25/2 = 35.
+	if want := 10.0 + 15 + 35; !near(s.Score, want) {
+		t.Errorf("Score %g, want %g", s.Score, want)
+	}
+	s.Skip()
+	s.Check()
+	if len(s.Answers) != 3 {
+		t.Error("a done session took more moves")
+	}
+}
+
+func TestSessionWrongCheck(t *testing.T) {
+	s := newTestSession(t, 2, Options{Scorer: &Flat{Points: 10, WrongPenalty: 2, SkipPenalty: 5}})
+	answer(s)
+	s.Check()
+	s.Skip()
+	miss(s)
+	if r := s.Check(); r.Correct || r.Fallacy || r.Credit != 0 {
+		t.Fatalf("got %+v, want wrong", r)
+	}
+	if s.Combo != 0 || s.Score != 8 || s.Done {
+		t.Errorf("after a wrong check: Combo %d, Score %g, Done %v; want 0, 8, false", s.Combo, s.Score, s.Done)
+	}
+	// The player may try again and still get the points.
+	answer(s)
+	if r := s.Check(); !r.Correct {
+		t.Fatalf("got %+v on the retry, want correct", r)
+	}
+	s.Skip()
+	if !s.Done || s.Score != 18 || s.Answers[1].Checks != 2 || !s.Answers[1].Correct {
+		t.Errorf("Done %v, Score %g, answer %+v", s.Done, s.Score, s.Answers[1])
+	}
+}
+
+func TestSessionPartialCredit(t *testing.T) {
+	s := newTestSession(t, 1, Options{Scorer: &Flat{Points: 10}})
+	for i, c := range s.Choices {
+		if c == s.Question.Name {
+			s.SelectChoice(i)
+		}
+	}
+	r := s.Check()
+	if r.Correct || !r.Fallacy || r.Credit != fallacyWeight {
+		t.Fatalf("got %+v, want the fallacy right and no phrases", r)
+	}
+	if s.ChoiceMark != Hit {
+		t.Errorf("ChoiceMark %v, want Hit", s.ChoiceMark)
+	}
+	s.Skip()
+	if !s.Done || s.Score != 10*fallacyWeight || s.Answers[0].Correct {
+		t.Errorf("Done %v, Score %g, answer %+v", s.Done, s.Score, s.Answers[0])
+	}
+}
+
+func TestSessionWrongFallacy(t *testing.T) {
+	s := newTestSession(t, 1, Options{Scorer: &Flat{Points: 10}})
+	miss(s)
+	for i := range s.Question.Phrases {
+		s.TogglePhrase(i)
+	}
+	r := s.Check()
+	if r.Fallacy || r.Credit != 0 || r.Recall != 1 {
+		t.Fatalf("got %+v, want no credit for the phrases under the wrong fallacy", r)
+	}
+	// The phrases are still marked, so the player can see which were right.
+	for _, a := range s.Question.Ans {
+		if s.Marks[a] != Hit {
+			t.Errorf("phrase %d marked %v, want Hit", a, s.Marks[a])
+		}
+	}
+	s.Skip()
+	if s.Score != 0 || s.Answers[0].Credit != 0 {
+		t.Errorf("Score %g, answer %+v", s.Score, s.Answers[0])
+	}
+}
+
+func TestSessionSkip(t *testing.T) {
+	s := newTestSession(t, 2, Options{Scorer: &Flat{Points: 10, SkipPenalty: 3}})
+	answer(s)
+	s.Check()
+	s.Skip()
+	s.Skip()
+	if !s.Done || s.Combo != 0 || s.Score != 7 {
+		t.Errorf("Done %v, Combo %d, Score %g; want true, 0, 7", s.Done, s.Combo, s.Score)
+	}
+	if len(s.Answers) != 2 || s.Answers[1].Correct || s.Answers[1].Checks != 0 {
+		t.Errorf("answers %+v", s.Answers)
+	}
+}
+
+func TestSessionLives(t *testing.T) {
+	s := newTestSession(t, 0, Options{Lives: 2})
+	miss(s)
+	s.Check()
+	if s.Done || s.LivesLeft() != 1 {
+		t.Fatalf("Done %v, %d lives left after one mistake", s.Done, s.LivesLeft())
+	}
+	// Skipping an unanswered question costs the last life.
+	s.Skip()
+	if !s.Done || s.LivesLeft() != 0 || len(s.Answers) != 1 {
+		t.Errorf("Done %v, %d lives left, %d answers", s.Done, s.LivesLeft(), len(s.Answers))
+	}
+
+	s = newTestSession(t, 0, Options{Lives: 1})
+	for i := 0; i < 20; i++ {
+		answer(s)
+		s.Check()
+		s.Skip()
+	}
+	if s.Done || s.Count != 21 {
+		t.Errorf("right answers cost lives: Done %v, Count %d", s.Done, s.Count)
+	}
+	miss(s)
+	s.Check()
+	if !s.Done || len(s.Answers) != 21 {
+		t.Errorf("Done %v with %d answers after losing the only life", s.Done, len(s.Answers))
+	}
+}
+
+func TestSessionTimeLimit(t *testing.T) {
+	s := newTestSession(t, 0, Options{Scorer: &Flat{Points: 10}, TimeLimit: 10})
+	answer(s)
+	s.Tick(3)
+	s.Check()
+	s.Skip()
+	if s.Timer != 0 || s.Elapsed != 3 || s.Remaining() != 7 {
+		t.Fatalf("Timer %g, Elapsed %g, Remaining %g", s.Timer, s.Elapsed, s.Remaining())
+	}
+	// A question answered right when time runs out still counts.
+	answer(s)
+	s.Check()
+	s.Tick(8)
+	if !s.Done || s.Elapsed != 10 || s.Remaining() != 0 {
+		t.Fatalf("Done %v, Elapsed %g after the limit", s.Done, s.Elapsed)
+	}
+	if len(s.Answers) != 2 || s.Score != 20 || s.Combo != 2 {
+		t.Errorf("%d answers, Score %g, Combo %d; want 2, 20, 2", len(s.Answers), s.Score, s.Combo)
+	}
+	s.Tick(1)
+	if s.Elapsed != 10 {
+		t.Error("the clock ran on after the session ended")
+	}
+
+	// One not yet answered is dropped.
+	s = newTestSession(t, 0, Options{TimeLimit: 5})
+	miss(s)
+	s.Tick(5)
+	if !s.Done || len(s.Answers) != 0 || s.Score != 0 {
+		t.Errorf("Done %v, %d answers, Score %g", s.Done, len(s.Answers), s.Score)
+	}
+}
+
+func TestSessionSeed(t *testing.T) {
+	play := func(seed int64) (names []string, choices [][]string) {
+		cat, questions := fixture(t)
+		rng := rand.New(rand.NewSource(seed))
+		deck, err := NewDeck(cat, questions, Filter{}, rng)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := NewSession(cat, questions, 6, Options{Picker: deck, Rand: rng})
+		for !s.Done {
+			names = append(names, s.Question.Name)
+			choices = append(choices, s.Choices)
+			s.Skip()
+		}
+		return names, choices
+	}
+	names, choices := play(7)
+	again, againChoices := play(7)
+	if !reflect.DeepEqual(names, again) || !reflect.DeepEqual(choices, againChoices) {
+		t.Errorf("the same seed played differently:\n%v %v\n%v %v", names, choices, again, againChoices)
+	}
+	seen := make(map[string]bool)
+	for _, name := range names {
+		seen[name] = true
+	}
+	if len(seen) != 6 {
+		t.Errorf("a deck of 6 dealt %v", names)
+	}
+}
+
+func TestSessionLocked(t *testing.T) {
+	s := newTestSession(t, 1, Options{})
+	answer(s)
+	s.Check()
+	chosen, selected := s.Chosen, append([]bool(nil), s.Selected...)
+	s.SelectChoice((chosen + 1) % len(s.Choices))
+	s.TogglePhrase(0)
+	if s.Chosen != chosen || !reflect.DeepEqual(s.Selected, selected) {
+		t.Error("answer changed after it was checked right")
+	}
+}
+
+func TestSessionEvents(t *testing.T) {
+	var bus events.Bus
+	var got []events.Event
+	bus.Subscribe(events.SinkFunc(func(r events.Record) {
+		got = append(got, r.Event)
+	}))
+	s := newTestSession(t, 2, Options{Scorer: &Flat{Points: 10, WrongPenalty: 2, SkipPenalty: 3}, Events: &bus})
+	answer(s)
+	s.Check()
+	s.Skip()
+	miss(s)
+	s.Check()
+	s.Skip()
+	// Scores carry the points as they are banked and the penalties as they
+	// are taken.
+	var scores []float64
+	for _, e := range got {
+		switch e := e.(type) {
+		case events.Checked:
+			scores = append(scores, e.Score)
+		case events.Answered:
+			scores = append(scores, e.Score)
+		}
+	}
+	if want := []float64{0, 10, 8, 5}; !reflect.DeepEqual(scores, want) {
+		t.Errorf("scores %v, want %v", scores, want)
+	}
+	if _, ok := got[0].(events.QuestionShown); !ok {
+		t.Errorf("first event %T, want QuestionShown", got[0])
+	}
+}
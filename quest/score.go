@@ -0,0 +1,201 @@
+package quest
+
+import (
+	"fmt"
+	"math"
+)
+
+// A Scorer decides how many points a session awards. The session keeps the
+// combo and banks Points when the player moves on; the scorer sets Gain and
+// Points and applies penalties to Score. Penalties shrink with the credit
+// the answer earned (Session.Last.Credit for Wrong, Session.Credit for Skip).
+type Scorer interface {
+	// Begin sets the points on offer for a new question.
+	Begin(s *Session)
+	// Correct sets the points a fully correct check would earn now.
+	Correct(s *Session)
+	// Wrong applies the penalty for an incorrect check.
+	Wrong(s *Session)
+	// Skip applies the penalty for skipping an unanswered question.
+	Skip(s *Session)
+}
+
+// Classic is the original scoring: the offer grows with combo and score,
+// decays with time, and mistakes cost a fraction of the score.
+type Classic struct {
+	Base         float64 `json:"base"`
+	ComboBonus   float64 `json:"comboBonus"`
+	Decay        float64 `json:"decay"`
+	Delay        float64 `json:"delay"`
+	Floor        float64 `json:"floor"`
+	WrongPenalty float64 `json:"wrongPenalty"`
+	SkipPenalty  float64 `json:"skipPenalty"`
+}
+
+func (c *Classic) Begin(s *Session) {
+	s.Gain = c.Base + float64(s.Combo)*s.Score*c.ComboBonus
+}
+
+func (c *Classic) Correct(s *Session) {
+	s.Points = math.Max(s.Gain*(c.Decay/(s.Timer+c.Delay)+c.Floor), 0)
+}
+
+func (c *Classic) Wrong(s *Session) {
+	penalty := c.WrongPenalty * (1 - s.Last.Credit)
+	s.Gain -= s.Gain * penalty
+	s.Score -= s.Score * penalty
+	s.Score = math.Max(s.Score, 0)
+}
+
+func (c *Classic) Skip(s *Session) {
+	s.Score -= s.Score * c.SkipPenalty * (1 - s.Credit)
+}
+
+// Flat awards the same points for every correct answer.
+type Flat struct {
+	Points       float64 `json:"points"`
+	WrongPenalty float64 `json:"wrongPenalty"`
+	SkipPenalty  float64 `json:"skipPenalty"`
+}
+
+func (f *Flat) Begin(s *Session) {
+	s.Gain = f.Points
+}
+
+func (f *Flat) Correct(s *Session) {
+	s.Points = s.Gain
+}
+
+func (f *Flat) Wrong(s *Session) {
+	s.Score = math.Max(s.Score-f.WrongPenalty*(1-s.Last.Credit), 0)
+}
+
+func (f *Flat) Skip(s *Session) {
+	s.Score = math.Max(s.Score-f.SkipPenalty*(1-s.Credit), 0)
+}
+
+// Accuracy scores the percentage of questions answered right on the first
+// check, with partial answers worth their credit. Time and combo are ignored.
+type Accuracy struct{}
+
+func (Accuracy) Begin(s *Session) {
+	s.Gain = 1
+	if s.Total > 0 {
+		s.Gain = 100 / float64(s.Total)
+	}
+}
+
+func (Accuracy) Correct(s *Session) {
+	s.Points = s.Gain
+}
+
+func (Accuracy) Wrong(s *Session) {
+	s.Gain *= s.Last.Credit
+}
+
+func (Accuracy) Skip(s *Session) {}
+
+// TimeAttack scores only speed. A wrong check adds time to the clock.
+type TimeAttack struct {
+	Max          float64 `json:"max"`
+	Min          float64 `json:"min"`
+	PerSecond    float64 `json:"perSecond"`
+	WrongSeconds float64 `json:"wrongSeconds"`
+}
+
+func (t *TimeAttack) Begin(s *Session) {
+	s.Gain = t.Max
+}
+
+func (t *TimeAttack) Correct(s *Session) {
+	s.Points = math.Max(t.Max-t.PerSecond*s.Timer, t.Min)
+}
+
+func (t *TimeAttack) Wrong(s *Session) {
+	s.Timer += t.WrongSeconds * (1 - s.Last.Credit)
+}
+
+func (t *TimeAttack) Skip(s *Session) {}
+
+// Blitz scores a race against the session clock. Each correct answer is
+// worth Points plus a Bonus that shrinks to nothing over BonusSeconds, so
+// quick answers pay; time spent on mistakes is the only penalty.
+type Blitz struct {
+	Points       float64 `json:"points"`
+	Bonus        float64 `json:"bonus"`
+	BonusSeconds float64 `json:"bonusSeconds"`
+}
+
+func (b *Blitz) Begin(s *Session) {
+	s.Gain = b.Points + b.Bonus
+}
+
+func (b *Blitz) Correct(s *Session) {
+	speed := 0.0
+	if b.BonusSeconds > 0 {
+		speed = math.Max(1-s.Timer/b.BonusSeconds, 0)
+	}
+	s.Points = b.Points + b.Bonus*speed
+}
+
+func (b *Blitz) Wrong(s *Session) {}
+
+func (b *Blitz) Skip(s *Session) {}
+
+// ScoringConfig selects a scorer by name and holds the tunables for each.
+// Blitz is always scored by the Blitz scorer.
+type ScoringConfig struct {
+	Scorer     string     `json:"scorer"`
+	Classic    Classic    `json:"classic"`
+	Flat       Flat       `json:"flat"`
+	TimeAttack TimeAttack `json:"timeAttack"`
+	Blitz      Blitz      `json:"blitz"`
+}
+
+// DefaultScoring returns the classic scorer with the original constants.
+func DefaultScoring() ScoringConfig {
+	return ScoringConfig{
+		Scorer: "classic",
+		Classic: Classic{
+			Base:         10,
+			ComboBonus:   .5,
+			Decay:        4,
+			Delay:        5,
+			Floor:        .2,
+			WrongPenalty: 1.0 / 16,
+			SkipPenalty:  1.0 / 4,
+		},
+		Flat: Flat{
+			Points: 10,
+		},
+		TimeAttack: TimeAttack{
+			Max:          100,
+			Min:          10,
+			PerSecond:    5,
+			WrongSeconds: 5,
+		},
+		Blitz: Blitz{
+			Points:       10,
+			Bonus:        10,
+			BonusSeconds: 10,
+		},
+	}
+}
+
+// NewScorer returns the scorer named in the config.
+func (c ScoringConfig) NewScorer() (Scorer, error) {
+	switch c.Scorer {
+	case "classic", "":
+		classic := c.Classic
+		return &classic, nil
+	case "flat":
+		flat := c.Flat
+		return &flat, nil
+	case "accuracy":
+		return Accuracy{}, nil
+	case "timeAttack":
+		timeAttack := c.TimeAttack
+		return &timeAttack, nil
+	}
+	return nil, fmt.Errorf("unknown scorer %q (want classic, flat, accuracy or timeAttack)", c.Scorer)
+}
@@ -0,0 +1,132 @@
+package quest
+
+import (
+	"strings"
+	"testing"
+)
+
+// scoreCase applies one scorer step to a session and checks the fields a
+// scorer may set.
+type scoreCase struct {
+	name  string
+	s     Session
+	step  func(Scorer, *Session)
+	gain  float64
+	point float64
+	score float64
+	timer float64
+}
+
+func begin(sc Scorer, s *Session)   { sc.Begin(s) }
+func correct(sc Scorer, s *Session) { sc.Correct(s) }
+func wrong(sc Scorer, s *Session)   { sc.Wrong(s) }
+func skip(sc Scorer, s *Session)    { sc.Skip(s) }
+
+func runScoreCases(t *testing.T, sc Scorer, cases []scoreCase) {
+	t.Helper()
+	for _, c := range cases {
+		s := c.s
+		c.step(sc, &s)
+		if !near(s.Gain, c.gain) || !near(s.Points, c.point) || !near(s.Score, c.score) || !near(s.Timer, c.timer) {
+			t.Errorf("%s: Gain %g, Points %g, Score %g, Timer %g; want %g, %g, %g, %g",
+				c.name, s.Gain, s.Points, s.Score, s.Timer, c.gain, c.point, c.score, c.timer)
+		}
+	}
+}
+
+func TestClassic(t *testing.T) {
+	classic := DefaultScoring().Classic
+	// The original game offered 10+combo*score/2 points, paid out
+	// gain*(4/(t+5)+.2) of them after t seconds, and took 1/16 of the score
+	// for a wrong check and 1/4 for a skip.
+	runScoreCases(t, &classic, []scoreCase{
+		{name: "first question", s: Session{}, step: begin, gain: 10},
+		{name: "offer grows with combo and score", s: Session{Combo: 3, Score: 40}, step: begin, gain: 10 + 3*40/2.0, score: 40},
+		{name: "no combo", s: Session{Combo: 0, Score: 40}, step: begin, gain: 10, score: 40},
+		{name: "instant answer", s: Session{Gain: 10}, step: correct, gain: 10, point: 10 * (4.0/5 + .2)},
+		{name: "slow answer", s: Session{Gain: 70, Timer: 15}, step: correct, gain: 70, point: 70 * (4.0/20 + .2), timer: 15},
+		{name: "wrong check", s: Session{Gain: 32, Score: 160}, step: wrong, gain: 32 - 32.0/16, score: 160 - 160.0/16},
+		{name: "half right check", s: Session{Gain: 32, Score: 160, Last: Result{Credit: .5}}, step: wrong, gain: 32 - 32.0/32, score: 160 - 160.0/32},
+		{name: "skip", s: Session{Score: 100}, step: skip, score: 75},
+		{name: "skip with half credit", s: Session{Score: 100, Credit: .5}, step: skip, score: 100 - 100.0/8},
+	})
+}
+
+func TestFlat(t *testing.T) {
+	runScoreCases(t, &Flat{Points: 10, WrongPenalty: 2, SkipPenalty: 4}, []scoreCase{
+		{name: "begin", s: Session{Combo: 5, Score: 100}, step: begin, gain: 10, score: 100},
+		{name: "correct ignores time", s: Session{Gain: 10, Timer: 60}, step: correct, gain: 10, point: 10, timer: 60},
+		{name: "wrong", s: Session{Score: 15}, step: wrong, score: 13},
+		{name: "half right", s: Session{Score: 15, Last: Result{Credit: .5}}, step: wrong, score: 14},
+		{name: "wrong at zero", s: Session{Score: 1}, step: wrong, score: 0},
+		{name: "skip", s: Session{Score: 15}, step: skip, score: 11},
+		{name: "skip at zero", s: Session{Score: 3}, step: skip, score: 0},
+	})
+}
+
+func TestAccuracy(t *testing.T) {
+	runScoreCases(t, Accuracy{}, []scoreCase{
+		{name: "share of the round", s: Session{Total: 8}, step: begin, gain: 12.5},
+		{name: "no length", s: Session{}, step: begin, gain: 1},
+		{name: "correct", s: Session{Gain: 12.5, Timer: 30}, step: correct, gain: 12.5, point: 12.5, timer: 30},
+		{name: "wrong keeps the credit earned", s: Session{Gain: 12.5, Last: Result{Credit: .5}}, step: wrong, gain: 6.25},
+		{name: "wrong", s: Session{Gain: 12.5}, step: wrong},
+		{name: "skip", s: Session{Score: 50}, step: skip, score: 50},
+	})
+}
+
+func TestTimeAttack(t *testing.T) {
+	runScoreCases(t, &TimeAttack{Max: 100, Min: 10, PerSecond: 5, WrongSeconds: 4}, []scoreCase{
+		{name: "begin", s: Session{}, step: begin, gain: 100},
+		{name: "instant", s: Session{}, step: correct, point: 100},
+		{name: "after 6s", s: Session{Timer: 6}, step: correct, point: 70, timer: 6},
+		{name: "floor", s: Session{Timer: 60}, step: correct, point: 10, timer: 60},
+		{name: "wrong adds time", s: Session{Timer: 2}, step: wrong, timer: 6},
+		{name: "half right adds less", s: Session{Timer: 2, Last: Result{Credit: .5}}, step: wrong, timer: 4},
+		{name: "skip", s: Session{Score: 50}, step: skip, score: 50},
+	})
+}
+
+func TestNewScorer(t *testing.T) {
+	config := DefaultScoring()
+	for name, want := range map[string]Scorer{
+		"":           &config.Classic,
+		"classic":    &config.Classic,
+		"flat":       &config.Flat,
+		"accuracy":   Accuracy{},
+		"timeAttack": &config.TimeAttack,
+	} {
+		config.Scorer = name
+		sc, err := config.NewScorer()
+		if err != nil {
+			t.Errorf("%q: %v", name, err)
+			continue
+		}
+		switch sc := sc.(type) {
+		case *Classic:
+			if w, ok := want.(*Classic); !ok || *sc != *w || sc == w {
+				t.Errorf("%q: got %+v", name, sc)
+			}
+		case *Flat:
+			if w, ok := want.(*Flat); !ok || *sc != *w || sc == w {
+				t.Errorf("%q: got %+v", name, sc)
+			}
+		case *TimeAttack:
+			if w, ok := want.(*TimeAttack); !ok || *sc != *w || sc == w {
+				t.Errorf("%q: got %+v", name, sc)
+			}
+		case Accuracy:
+			if _, ok := want.(Accuracy); !ok {
+				t.Errorf("%q: got %+v", name, sc)
+			}
+		default:
+			t.Errorf("%q: got %T", name, sc)
+		}
+	}
+
+	config.Scorer = "golf"
+	sc, err := config.NewScorer()
+	if sc != nil || err == nil || !strings.Contains(err.Error(), `"golf"`) {
+		t.Errorf("unknown scorer: got %v, %v", sc, err)
+	}
+}
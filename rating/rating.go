@@ -0,0 +1,114 @@
+// Package rating keeps Elo ratings for a player and the questions they
+// answer. Each answer is a match: the player wins by answering right and
+// the question wins otherwise, so questions people get wrong or answer
+// slowly climb, and a question's rating becomes its difficulty.
+package rating
+
+import (
+	"github.com/dkeriazisStuy/FallacyQuest/content"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+const (
+	// Start is the rating of a new player and of a Medium question.
+	Start = 1000.0
+	// LevelStep is how far apart the starting ratings of the levels are.
+	LevelStep = 200.0
+	// PlayerK and QuestionK are how far one answer moves each rating.
+	PlayerK   = 32.0
+	QuestionK = 16.0
+	// SlowSeconds is how long a right answer can take at full value.
+	SlowSeconds = 30.0
+	// Offset is where the adaptive pick aims relative to the player: a bit
+	// below, so the player wins about two times in three.
+	Offset = -120.0
+	// candidates is how many of the closest questions a pick chooses from.
+	candidates = 3
+)
+
+// Book holds one player's rating and their view of each question's rating,
+// keyed by question ID.
+type Book struct {
+	Player    float64            `json:"player"`
+	Questions map[string]float64 `json:"questions"`
+}
+
+// NewBook returns the ratings of a new player.
+func NewBook() *Book {
+	return &Book{Player: Start, Questions: make(map[string]float64)}
+}
+
+// Copy returns a book that can be changed without touching b.
+func (b *Book) Copy() *Book {
+	c := &Book{Player: b.Player, Questions: make(map[string]float64, len(b.Questions))}
+	for id, r := range b.Questions {
+		c.Questions[id] = r
+	}
+	return c
+}
+
+// Question is the rating of q, starting from its level.
+func (b *Book) Question(q *content.Question) float64 {
+	if r, ok := b.Questions[q.ID()]; ok {
+		return r
+	}
+	return Start + LevelStep*float64(q.Level()-content.Medium)
+}
+
+// Expected is the chance that a player rated player beats a question rated
+// question.
+func Expected(player, question float64) float64 {
+	return 1 / (1 + math.Pow(10, (question-player)/400))
+}
+
+// Outcome scores an answer from the player's side, from 0 to 1. Each extra
+// check costs a quarter, a slow answer a quarter of what is left, and a
+// wrong answer is worth half its credit.
+func Outcome(correct bool, checks int, credit, seconds float64) float64 {
+	if !correct {
+		return credit / 2
+	}
+	score := math.Max(1-.25*float64(checks-1), .5)
+	if seconds > SlowSeconds {
+		score *= .75
+	}
+	return score
+}
+
+// Update rates an answer to q with the given outcome.
+func (b *Book) Update(q *content.Question, outcome float64) {
+	question := b.Question(q)
+	diff := outcome - Expected(b.Player, question)
+	b.Player += PlayerK * diff
+	b.Questions[q.ID()] = question - QuestionK*diff
+}
+
+// Next picks a question rated close to Offset from the player, at random
+// among the few closest. Questions whose IDs are in asked are passed over
+// unless every question has been asked.
+func (b *Book) Next(questions []content.Question, asked map[string]bool, rng *rand.Rand) *content.Question {
+	var pool []*content.Question
+	for i := range questions {
+		if !asked[questions[i].ID()] {
+			pool = append(pool, &questions[i])
+		}
+	}
+	if len(pool) == 0 {
+		for i := range questions {
+			pool = append(pool, &questions[i])
+		}
+	}
+	if len(pool) == 0 {
+		return nil
+	}
+	target := b.Player + Offset
+	sort.SliceStable(pool, func(i, j int) bool {
+		return math.Abs(b.Question(pool[i])-target) < math.Abs(b.Question(pool[j])-target)
+	})
+	if len(pool) > candidates {
+		pool = pool[:candidates]
+	}
+	return pool[rng.Intn(len(pool))]
+}
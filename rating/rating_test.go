@@ -0,0 +1,116 @@
+package rating
+
+import (
+	"github.com/dkeriazisStuy/FallacyQuest/content"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestOutcome(t *testing.T) {
+	for _, tt := range []struct {
+		correct bool
+		checks  int
+		credit  float64
+		seconds float64
+		want    float64
+	}{
+		{true, 1, 1, 5, 1},
+		{true, 2, 1, 5, .75},
+		{true, 3, 1, 5, .5},
+		{true, 9, 1, 5, .5},
+		{true, 1, 1, 60, .75},
+		{true, 2, 1, 60, .5625},
+		{false, 1, 0, 5, 0},
+		{false, 2, .75, 5, .375},
+	} {
+		if got := Outcome(tt.correct, tt.checks, tt.credit, tt.seconds); !near(got, tt.want) {
+			t.Errorf("Outcome(%v, %d, %g, %g) = %g, want %g", tt.correct, tt.checks, tt.credit, tt.seconds, got, tt.want)
+		}
+	}
+}
+
+func TestExpected(t *testing.T) {
+	if e := Expected(Start, Start); !near(e, .5) {
+		t.Errorf("even match: %g", e)
+	}
+	if e := Expected(1400, 1000); !near(e, 1/1.1) {
+		t.Errorf("400 ahead: %g", e)
+	}
+	if a, b := Expected(1200, 1000), Expected(1000, 1200); !near(a+b, 1) {
+		t.Errorf("expectations %g and %g don't add up to 1", a, b)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	easy := &content.Question{Name: "straw", Phrases: []string{"a"}, Difficulty: content.Easy}
+	hard := &content.Question{Name: "post", Phrases: []string{"b"}, Difficulty: content.Hard}
+	b := NewBook()
+	if r := b.Question(easy); r != Start-LevelStep {
+		t.Errorf("a new easy question rated %g", r)
+	}
+	if r := b.Question(hard); r != Start+LevelStep {
+		t.Errorf("a new hard question rated %g", r)
+	}
+
+	// An even match won moves each side by half its K.
+	medium := &content.Question{Name: "cum", Phrases: []string{"c"}, Difficulty: content.Medium}
+	b.Update(medium, 1)
+	if !near(b.Player, Start+PlayerK/2) || !near(b.Questions[medium.ID()], Start-QuestionK/2) {
+		t.Errorf("after a win: player %g, question %g", b.Player, b.Questions[medium.ID()])
+	}
+	// Losing to a hard question costs less than beating it gains.
+	before, copied := b.Player, b.Copy()
+	b.Update(hard, 0)
+	lost := before - b.Player
+	copied.Update(hard, 1)
+	won := copied.Player - before
+	if lost <= 0 || won <= lost {
+		t.Errorf("lost %g to a hard question, won %g", lost, won)
+	}
+	if b.Questions[hard.ID()] <= Start+LevelStep {
+		t.Error("a question the player got wrong didn't climb")
+	}
+	if copied.Questions[hard.ID()] == b.Questions[hard.ID()] {
+		t.Error("the copy shares the book's ratings")
+	}
+}
+
+func TestNext(t *testing.T) {
+	var questions []content.Question
+	for level := content.Easy; level <= content.Hard; level++ {
+		for i := 0; i < 5; i++ {
+			questions = append(questions, content.Question{Name: "straw", Phrases: []string{string(rune('a' + level)), string(rune('a' + i))}, Difficulty: level})
+		}
+	}
+	rng := rand.New(rand.NewSource(1))
+	b := NewBook()
+	asked := make(map[string]bool)
+	// Picks aim a little below the player, which for a new one is nearer
+	// the easy questions than the medium ones.
+	for i := 0; i < 3; i++ {
+		q := b.Next(questions, asked, rng)
+		if q.Level() != content.Easy || asked[q.ID()] {
+			t.Fatalf("pick %d: level %d, asked before %v", i, q.Level(), asked[q.ID()])
+		}
+		asked[q.ID()] = true
+	}
+	// A strong player gets the hard ones.
+	b.Player = Start + 2*LevelStep
+	if q := b.Next(questions, nil, rng); q.Level() != content.Hard {
+		t.Errorf("a strong player got level %d", q.Level())
+	}
+	for _, q := range questions {
+		asked[q.ID()] = true
+	}
+	if q := b.Next(questions, asked, rng); q == nil {
+		t.Error("got nothing with every question asked")
+	}
+	if q := b.Next(nil, nil, rng); q != nil {
+		t.Errorf("got %v from no questions", q)
+	}
+}
@@ -0,0 +1,162 @@
+// Package replay records the input and events of a session, so it can be
+// played back frame by frame through the game's own logic.
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/dkeriazisStuy/FallacyQuest/content"
+	"github.com/dkeriazisStuy/FallacyQuest/events"
+	"github.com/dkeriazisStuy/FallacyQuest/input"
+	"github.com/dkeriazisStuy/FallacyQuest/quest"
+	"github.com/dkeriazisStuy/FallacyQuest/storage"
+	"github.com/faiface/pixel"
+	"math/rand"
+	"os"
+)
+
+// Version is the recording format written by this package.
+const Version = 1
+
+// Recording is everything needed to play a session back: how it was set
+// up, the input of every frame and the events it gave off. Seconds is the
+// time limit of a blitz round; Total and Filter set up a practice round.
+type Recording struct {
+	Version int                 `json:"version"`
+	Mode    string              `json:"mode"`
+	Player  string              `json:"player,omitempty"`
+	Seed    int64               `json:"seed"`
+	Seconds int                 `json:"seconds,omitempty"`
+	Total   int                 `json:"total,omitempty"`
+	Filter  quest.Filter        `json:"filter"`
+	Scoring quest.ScoringConfig `json:"scoring"`
+	Frames  []Frame             `json:"frames"`
+	Events  []events.Record     `json:"events"`
+	// size is the window size as of the last frame added.
+	size pixel.Vec
+}
+
+// Frame is the input of one frame and the seconds it lasted. Size is the
+// window size, given on the first frame and whenever it changes, since
+// pointer positions only make sense against it.
+type Frame struct {
+	DT    float64     `json:"dt"`
+	Input input.Frame `json:"input"`
+	Size  *pixel.Vec  `json:"size,omitempty"`
+}
+
+// Add records the input of a frame of dt seconds in a window of size.
+func (r *Recording) Add(dt float64, in input.Frame, size pixel.Vec) {
+	f := Frame{DT: dt, Input: in}
+	if r.size != size {
+		r.size = size
+		f.Size = &size
+	}
+	r.Frames = append(r.Frames, f)
+}
+
+// Handle records the session's events.
+func (r *Recording) Handle(rec events.Record) {
+	r.Events = append(r.Events, rec)
+}
+
+// Save writes r to path.
+func Save(path string, r *Recording) error {
+	r.Version = Version
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return storage.WriteFile(path, data, 0644)
+}
+
+// Load reads the recording at path.
+func Load(path string) (*Recording, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var r Recording
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if r.Version > Version {
+		return nil, fmt.Errorf("%s: recorded by a newer version of the game (format %d, want %d)", path, r.Version, Version)
+	}
+	return &r, nil
+}
+
+// Check reports whether r can be played back with the content loaded now:
+// its scoring must be known, its filter must match some question and every
+// question it showed must be in the bank, offering only fallacies in the
+// catalog.
+func (r *Recording) Check(cat *content.Catalog, questions []content.Question) error {
+	if _, err := r.Scoring.NewScorer(); err != nil {
+		return err
+	}
+	if _, err := quest.NewDeck(cat, questions, r.Filter, rand.New(rand.NewSource(r.Seed))); err != nil {
+		return err
+	}
+	if err := Script(r).Check(cat, questions); err != nil {
+		return fmt.Errorf("replay: %v", err)
+	}
+	return nil
+}
+
+// Playback plays a recording back a frame at a time, scaling pointer
+// positions to the window it is played in.
+type Playback struct {
+	rec  *Recording
+	next int
+	size pixel.Vec
+}
+
+// NewPlayback starts at the first frame of rec.
+func NewPlayback(rec *Recording) *Playback {
+	return &Playback{rec: rec}
+}
+
+// Done reports whether every frame has been played.
+func (p *Playback) Done() bool {
+	return p.next >= len(p.rec.Frames)
+}
+
+// Pos is the number of frames played and Len the number recorded.
+func (p *Playback) Pos() int { return p.next }
+func (p *Playback) Len() int { return len(p.rec.Frames) }
+
+// DT is the length of the next frame.
+func (p *Playback) DT() float64 {
+	if p.Done() {
+		return 0
+	}
+	return p.rec.Frames[p.next].DT
+}
+
+// Next returns the next frame's length and input, with the pointer moved
+// from the recorded window size into a window of size.
+func (p *Playback) Next(size pixel.Vec) (float64, input.Frame) {
+	f := p.rec.Frames[p.next]
+	p.next += 1
+	if f.Size != nil {
+		p.size = *f.Size
+	}
+	in := f.Input
+	if p.size.X > 0 && p.size.Y > 0 {
+		in.Pointer = pixel.V(in.Pointer.X*size.X/p.size.X, in.Pointer.Y*size.Y/p.size.Y)
+	}
+	return f.DT, in
+}
+
+// Script serves the questions and choices rec showed, in order, so a
+// replay asks what the session asked whatever the profile or settings are
+// now.
+func Script(rec *Recording) *quest.Sequence {
+	seq := &quest.Sequence{}
+	for _, r := range rec.Events {
+		if e, ok := r.Event.(events.QuestionShown); ok {
+			seq.Items = append(seq.Items, quest.Item{Question: e.Question, Fallacy: e.Fallacy, Choices: e.Choices})
+		}
+	}
+	return seq
+}
@@ -0,0 +1,67 @@
+package replay
+
+import (
+	"github.com/dkeriazisStuy/FallacyQuest/content"
+	"github.com/dkeriazisStuy/FallacyQuest/events"
+	"github.com/dkeriazisStuy/FallacyQuest/input"
+	"github.com/dkeriazisStuy/FallacyQuest/quest"
+	"github.com/faiface/pixel"
+	"testing"
+)
+
+func TestAddSize(t *testing.T) {
+	var r Recording
+	small, big := pixel.V(800, 600), pixel.V(1024, 768)
+	for _, size := range []pixel.Vec{small, small, big, big, small} {
+		r.Add(1.0/60, input.Frame{}, size)
+	}
+	var sizes []pixel.Vec
+	for i, f := range r.Frames {
+		if f.Size != nil {
+			sizes = append(sizes, *f.Size)
+			if i != 0 && i != 2 && i != 4 {
+				t.Errorf("size given on frame %d", i)
+			}
+		}
+	}
+	if len(sizes) != 3 || sizes[0] != small || sizes[1] != big || sizes[2] != small {
+		t.Errorf("got sizes %v", sizes)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	cat, err := content.LoadCatalog(content.Builtin())
+	if err != nil {
+		t.Fatal(err)
+	}
+	questions, err := content.Load(content.Builtin(), cat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := questions[0]
+	shown := func(choices ...string) events.Record {
+		return events.Record{Event: events.QuestionShown{Number: 1, Question: q.ID(), Fallacy: q.Name, Choices: choices}}
+	}
+	other := func(id, fallacy string) events.Record {
+		return events.Record{Event: events.QuestionShown{Number: 2, Question: id, Fallacy: fallacy, Choices: []string{fallacy}}}
+	}
+	wrong := cat.Fallacies[0].Key
+	if wrong == q.Name {
+		wrong = cat.Fallacies[1].Key
+	}
+	ok := Recording{Scoring: quest.DefaultScoring(), Events: []events.Record{shown(q.Name)}}
+	if err := ok.Check(cat, questions); err != nil {
+		t.Errorf("good recording: %v", err)
+	}
+	for name, r := range map[string]Recording{
+		"scorer":   {Scoring: quest.ScoringConfig{Scorer: "golf"}},
+		"filter":   {Scoring: quest.DefaultScoring(), Filter: quest.Filter{Fallacies: []string{"nope"}}},
+		"choices":  {Scoring: quest.DefaultScoring(), Events: []events.Record{shown(q.Name, "nope")}},
+		"answer":   {Scoring: quest.DefaultScoring(), Events: []events.Record{shown(q.Name), other(q.ID(), wrong)}},
+		"question": {Scoring: quest.DefaultScoring(), Events: []events.Record{shown(q.Name), other("0123456789abcdef", q.Name)}},
+	} {
+		if err := r.Check(cat, questions); err == nil {
+			t.Errorf("bad %s: no error", name)
+		}
+	}
+}
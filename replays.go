@@ -0,0 +1,353 @@
+package main
+
+import (
+	"fmt"
+	"github.com/dkeriazisStuy/FallacyQuest/events"
+	"github.com/dkeriazisStuy/FallacyQuest/input"
+	"github.com/dkeriazisStuy/FallacyQuest/replay"
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// replayKeep is how many recordings are kept; older ones are deleted.
+	replayKeep = 20
+	replayRows = 6
+	// replayStamp names recordings by when they started.
+	replayStamp = "20060102-150405"
+)
+
+// replaySpeeds are the playback speeds, starting from replaySpeed.
+var replaySpeeds = []float64{0.25, 0.5, 1, 2, 4, 8}
+
+const replaySpeed = 2
+
+func replayDir() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "replays"), nil
+}
+
+// saveReplay writes rec to the replay directory, named by when and what was
+// played, and deletes the oldest recordings beyond replayKeep.
+func saveReplay(rec *replay.Recording) {
+	dir, err := replayDir()
+	if err != nil {
+		return
+	}
+	start := time.Now()
+	if len(rec.Events) > 0 {
+		start = rec.Events[0].Time
+	}
+	path := filepath.Join(dir, start.Format(replayStamp)+"-"+rec.Mode+".json")
+	if err := replay.Save(path, rec); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	paths := replayFiles(dir)
+	for len(paths) > replayKeep {
+		os.Remove(paths[len(paths)-1])
+		paths = paths[:len(paths)-1]
+	}
+}
+
+// replayFiles lists the recordings in dir, newest first.
+func replayFiles(dir string) []string {
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	sort.Sort(sort.Reverse(sort.StringSlice(paths)))
+	return paths
+}
+
+// replayLabel describes a recording by its file name.
+func replayLabel(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), ".json")
+	if len(name) <= len(replayStamp)+1 {
+		return name
+	}
+	start, err := time.ParseInLocation(replayStamp, name[:len(replayStamp)], time.Local)
+	if err != nil {
+		return name
+	}
+	return start.Format("Jan 2 15:04") + "  " + name[len(replayStamp)+1:]
+}
+
+// replayList offers every recording kept to watch, a page at a time.
+type replayList struct {
+	st       *sceneStack
+	paths    []string
+	rows     []button
+	rowTxts  []*text.Text
+	pager    pager
+	back     button
+	backIcon *imdraw.IMDraw
+	focus    focusList
+	// Text
+	titleTxt, errTxt *text.Text
+}
+
+func newReplayList(st *sceneStack) *replayList {
+	return &replayList{st: st}
+}
+
+func (l *replayList) enter() {
+	l.paths = nil
+	if dir, err := replayDir(); err == nil {
+		l.paths = replayFiles(dir)
+	}
+	l.pager = newPager(replayRows, len(l.paths))
+	l.rowTxts = nil
+	for _, path := range l.paths {
+		txt := text.New(pixel.ZV, atlas)
+		fmt.Fprint(txt, replayLabel(path))
+		l.rowTxts = append(l.rowTxts, txt)
+	}
+	l.titleTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprint(l.titleTxt, "Replays")
+	l.errTxt = text.New(pixel.ZV, atlas)
+	l.errTxt.Color = colornames.Yellow
+	if len(l.paths) == 0 {
+		fmt.Fprint(l.errTxt, "No sessions recorded yet")
+	}
+}
+
+func (l *replayList) exit() {}
+
+func (l *replayList) onResize() {
+	win := l.st.win
+	l.back, l.backIcon = backButton(win)
+	l.layoutRows()
+	l.pager.onResize(win, 3*winY/4)
+}
+
+// layoutRows makes a button for each recording on the current page.
+func (l *replayList) layoutRows() {
+	l.rows = nil
+	from, to := l.pager.span()
+	for i := range l.paths[from:to] {
+		pos := pixel.V(winX/2, 3*winY/4-float64(i)*winY*profileRowSize/origY)
+		l.rows = append(l.rows, newButton(l.st.win, centeredRect(pos, 200, 25), colornames.Sandybrown, colornames.Rosybrown))
+	}
+}
+
+func (l *replayList) update(dt float64) {
+	var focusable []*button
+	for i := range l.rows {
+		focusable = append(focusable, &l.rows[i])
+	}
+	l.focus.update(append(focusable, l.pager.buttons()...)...)
+	if l.back.check() || in.JustPressed(input.Back) {
+		l.st.pop()
+		return
+	}
+	if l.pager.update() {
+		l.layoutRows()
+		return
+	}
+	from, _ := l.pager.span()
+	for i := range l.rows {
+		if l.rows[i].check() {
+			rec, err := replay.Load(l.paths[from+i])
+			if err == nil {
+				err = rec.Check(catalog, questions)
+			}
+			if err != nil {
+				l.errTxt.Clear()
+				fmt.Fprint(l.errTxt, err)
+				return
+			}
+			l.st.push(newReplayScene(l.st, rec))
+			return
+		}
+	}
+}
+
+func (l *replayList) draw() {
+	win := l.st.win
+	drawText(win, l.titleTxt, pixel.V(winX/2, 7*winY/8), 4)
+	from, _ := l.pager.span()
+	for i := range l.rows {
+		l.rows[i].draw()
+		drawText(win, l.rowTxts[from+i], l.rows[i].rect.Center(), 2)
+	}
+	l.pager.draw(win)
+	drawText(win, l.errTxt, pixel.V(winX/2, winY/6), 1.5)
+	// Back
+	l.back.draw()
+	l.backIcon.Draw(win)
+}
+
+// replayScene plays a recording back through a quiz scene of its own,
+// feeding it the recorded input a frame at a time. Toggle pauses, Next
+// steps a frame while paused and Pick1 onwards choose a speed.
+type replayScene struct {
+	st  *sceneStack
+	rec *replay.Recording
+	// The quiz runs on a stack of its own, so that leaving or finishing
+	// only ends the replay.
+	inner *sceneStack
+	q     *quizScene
+	tape  *replay.Playback
+	// last is the recorded input of the latest frame played.
+	last   input.Frame
+	paused bool
+	speed  int
+	// acc is the playback time not yet spent on frames.
+	acc float64
+	// seen counts the events replayed, and diverged is set once one
+	// differs from the recording.
+	seen     int
+	diverged bool
+	// Widgets
+	pause, step, slower, faster, back button
+	backIcon                          *imdraw.IMDraw
+	pauseTxt, stepTxt, statusTxt      *text.Text
+	slowerTxt, fasterTxt, warnTxt     *text.Text
+}
+
+func newReplayScene(st *sceneStack, rec *replay.Recording) *replayScene {
+	return &replayScene{st: st, rec: rec, speed: replaySpeed}
+}
+
+func (r *replayScene) enter() {
+	r.inner = &sceneStack{win: r.st.win}
+	r.q = newQuizScene(r.inner, r.rec.Mode)
+	r.q.seconds = r.rec.Seconds
+	r.q.total = r.rec.Total
+	r.q.filter = r.rec.Filter
+	r.q.replaying = r.rec
+	r.q.events.Subscribe(events.SinkFunc(r.compare))
+	r.tape = replay.NewPlayback(r.rec)
+	r.pauseTxt = text.New(pixel.ZV, atlas)
+	r.stepTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprint(r.stepTxt, "Step")
+	r.slowerTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprint(r.slowerTxt, "-")
+	r.fasterTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprint(r.fasterTxt, "+")
+	r.statusTxt = text.New(pixel.ZV, atlas)
+	r.warnTxt = text.New(pixel.ZV, atlas)
+	r.warnTxt.Color = colornames.Yellow
+	r.inner.push(r.q)
+}
+
+func (r *replayScene) exit() {}
+
+// compare checks each replayed event against the recording.
+func (r *replayScene) compare(rec events.Record) {
+	if !r.diverged && (r.seen >= len(r.rec.Events) || !reflect.DeepEqual(rec.Event, r.rec.Events[r.seen].Event)) {
+		r.diverged = true
+		fmt.Fprintf(r.warnTxt, "Replay differs from the recording at event %d", r.seen+1)
+	}
+	r.seen += 1
+}
+
+func (r *replayScene) onResize() {
+	win := r.st.win
+	r.q.onResize()
+	r.back, r.backIcon = backButton(win)
+	y := winY - winY*30/origY
+	r.slower = newButton(win, centeredRect(pixel.V(winX-winX*330/origX, y), 15, 15), colornames.Sandybrown, colornames.Rosybrown)
+	r.pause = newButton(win, centeredRect(pixel.V(winX-winX*260/origX, y), 45, 15), colornames.Sandybrown, colornames.Rosybrown)
+	r.step = newButton(win, centeredRect(pixel.V(winX-winX*160/origX, y), 45, 15), colornames.Sandybrown, colornames.Rosybrown)
+	r.faster = newButton(win, centeredRect(pixel.V(winX-winX*90/origX, y), 15, 15), colornames.Sandybrown, colornames.Rosybrown)
+}
+
+// ended reports whether the recording has played out or the quiz is over.
+func (r *replayScene) ended() bool {
+	return r.tape.Done() || len(r.inner.scenes) == 0
+}
+
+// advance plays one recorded frame.
+func (r *replayScene) advance() {
+	live := in
+	var dt float64
+	dt, in = r.tape.Next(pixel.V(winX, winY))
+	r.last = in
+	r.inner.top().update(dt)
+	in = live
+}
+
+func (r *replayScene) update(dt float64) {
+	if r.back.check() || in.JustPressed(input.Back) {
+		r.st.pop()
+		return
+	}
+	if r.pause.check() || in.JustPressed(input.Toggle) {
+		r.paused = !r.paused
+	}
+	if r.slower.check() && r.speed > 0 {
+		r.speed -= 1
+	}
+	if r.faster.check() && r.speed < len(replaySpeeds)-1 {
+		r.speed += 1
+	}
+	for i := range replaySpeeds {
+		if in.JustPressed(input.Pick1 + input.Action(i)) {
+			r.speed = i
+		}
+	}
+	stepped := r.step.check() || in.JustPressed(input.Next)
+	switch {
+	case r.ended():
+	case r.paused:
+		r.acc = 0
+		if stepped {
+			r.advance()
+		}
+	default:
+		r.acc += dt * replaySpeeds[r.speed]
+		for !r.ended() && r.acc >= r.tape.DT() {
+			r.acc -= r.tape.DT()
+			r.advance()
+		}
+	}
+}
+
+func (r *replayScene) draw() {
+	win := r.st.win
+	// The quiz sees the recorded pointer, so hovering shows as it did.
+	live := in
+	in = r.last
+	r.q.draw()
+	in = live
+	cursor := imdraw.New(nil)
+	cursor.Color = colornames.White
+	p := r.last.Pointer
+	cursor.Push(p, p.Add(pixel.V(0, -winY*18/origY)), p.Add(pixel.V(winX*12/origX, -winY*13/origY)))
+	cursor.Polygon(0)
+	cursor.Draw(win)
+	// Controls
+	r.pauseTxt.Clear()
+	if r.paused {
+		fmt.Fprint(r.pauseTxt, "Play")
+	} else {
+		fmt.Fprint(r.pauseTxt, "Pause")
+	}
+	for _, b := range []struct {
+		b   *button
+		txt *text.Text
+	}{{&r.slower, r.slowerTxt}, {&r.pause, r.pauseTxt}, {&r.step, r.stepTxt}, {&r.faster, r.fasterTxt}} {
+		b.b.draw()
+		drawText(win, b.txt, b.b.rect.Center(), 2)
+	}
+	r.statusTxt.Clear()
+	fmt.Fprintf(r.statusTxt, "%gx  frame %d/%d", replaySpeeds[r.speed], r.tape.Pos(), r.tape.Len())
+	if r.ended() {
+		fmt.Fprint(r.statusTxt, "  (end)")
+	}
+	drawText(win, r.statusTxt, pixel.V(winX-winX*210/origX, winY-winY*70/origY), 1.5)
+	drawText(win, r.warnTxt, pixel.V(winX/2, winY*20/origY), 1.5)
+	// Back
+	r.back.draw()
+	r.backIcon.Draw(win)
+}
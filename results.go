@@ -0,0 +1,231 @@
+package main
+
+import (
+	"fmt"
+	"github.com/dkeriazisStuy/FallacyQuest/highscore"
+	"github.com/dkeriazisStuy/FallacyQuest/input"
+	"github.com/dkeriazisStuy/FallacyQuest/quest"
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const maxInitials = 3
+
+func highscorePath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "highscores.json"), nil
+}
+
+type winScene struct {
+	st     *sceneStack
+	s      *quest.Session
+	board  string
+	seed   *int64
+	replay func() scene
+	// High scores
+	table    *highscore.Table
+	rank     int
+	entering bool
+	initials string
+	// Widgets
+	menu, replayButton, save                          button
+	congratsTxt, scoreTxt, rankTxt, boardTxt, seedTxt *text.Text
+	statsTxt                                          *text.Text
+	initialsTxt, menuTxt, replayTxt, saveTxt          *text.Text
+	focus                                             focusList
+}
+
+// newWinScene shows the results of s. Sessions with a board are offered a
+// place on that high score board and shown the seed, which replays the same
+// questions when passed to -seed; seed is nil for rounds it doesn't replay.
+// replay starts a new session; it is nil when the session can't be played
+// again, as in a classroom round.
+func newWinScene(st *sceneStack, s *quest.Session, board string, seed *int64, replay func() scene) *winScene {
+	return &winScene{st: st, s: s, board: board, seed: seed, replay: replay}
+}
+
+func (w *winScene) enter() {
+	w.congratsTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprint(w.congratsTxt, "Congratulations!")
+	w.scoreTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprintf(w.scoreTxt, "Score: %.2f", w.s.Score)
+	w.statsTxt = text.New(pixel.ZV, atlas)
+	switch {
+	case w.s.Lives > 0:
+		fmt.Fprintf(w.statsTxt, "Lasted %d questions, %.0f%% accuracy", len(w.s.Answers), w.s.Accuracy()*100)
+	case w.s.TimeLimit > 0:
+		perMinute := float64(len(w.s.Answers)) / (w.s.Elapsed / 60)
+		fmt.Fprintf(w.statsTxt, "%d answered, %.1f per minute, %.0f%% accuracy", len(w.s.Answers), perMinute, w.s.Accuracy()*100)
+	}
+	w.seedTxt = text.New(pixel.ZV, atlas)
+	if w.seed != nil {
+		fmt.Fprintf(w.seedTxt, "Seed: %d", *w.seed)
+	}
+	w.rankTxt = text.New(pixel.ZV, atlas)
+	w.boardTxt = text.New(pixel.ZV, atlas)
+	w.initialsTxt = text.New(pixel.ZV, atlas)
+	w.menuTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprint(w.menuTxt, "Menu")
+	w.replayTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprint(w.replayTxt, "Play again")
+	w.saveTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprint(w.saveTxt, "Save")
+	if w.board == "" {
+		return
+	}
+	path, err := highscorePath()
+	if err != nil {
+		return
+	}
+	w.table, err = highscore.Load(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	w.rank = w.table.Rank(w.board, w.s.Score)
+	w.entering = w.rank > 0 && w.s.Score > 0
+	if w.entering && player != nil {
+		for _, word := range strings.Fields(player.Name) {
+			if len(w.initials) < maxInitials && isInitial(rune(word[0])) {
+				w.initials += strings.ToUpper(word[:1])
+			}
+		}
+	}
+	w.writeBoard()
+}
+
+func (w *winScene) exit() {}
+
+func (w *winScene) onResize() {
+	w.menu = newButton(w.st.win, pixel.R(winX/2-winX*210/origX, winY/8-winY*40/origY, winX/2-winX*10/origX, winY/8+winY*40/origY), colornames.Sandybrown, colornames.Rosybrown)
+	w.replayButton = newButton(w.st.win, pixel.R(winX/2+winX*10/origX, winY/8-winY*40/origY, winX/2+winX*210/origX, winY/8+winY*40/origY), colornames.Green, colornames.Darkgreen)
+	w.save = newButton(w.st.win, centeredRect(pixel.V(winX/2+winX*120/origX, 5*winY/8), 60, 20), colornames.Green, colornames.Darkgreen)
+}
+
+func isInitial(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+}
+
+func (w *winScene) update(dt float64) {
+	if w.entering {
+		for _, r := range in.Text {
+			if isInitial(r) && len(w.initials) < maxInitials {
+				w.initials += strings.ToUpper(string(r))
+			}
+		}
+		if in.JustPressed(input.Erase) && len(w.initials) > 0 {
+			w.initials = w.initials[:len(w.initials)-1]
+		}
+		if (w.save.check() || in.JustPressed(input.Confirm)) && w.initials != "" {
+			w.submit()
+		}
+		// Back leaves the board as it was.
+		if in.JustPressed(input.Back) {
+			w.entering = false
+			w.writeBoard()
+		}
+		return
+	}
+	if w.replay != nil {
+		w.focus.update(&w.menu, &w.replayButton)
+	} else {
+		w.focus.update(&w.menu)
+	}
+	switch {
+	case w.menu.check() || in.JustPressed(input.Back):
+		w.st.pop()
+	case w.replay != nil && w.replayButton.check():
+		w.st.replace(w.replay())
+	}
+}
+
+func (w *winScene) submit() {
+	e := highscore.Entry{Initials: w.initials, Score: w.s.Score, Date: time.Now()}
+	if player != nil {
+		e.Player = player.Name
+	}
+	w.rank = w.table.Add(w.board, e)
+	w.entering = false
+	if path, err := highscorePath(); err == nil {
+		if err := w.table.Save(path); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}
+	w.writeBoard()
+}
+
+func (w *winScene) writeBoard() {
+	w.rankTxt.Clear()
+	switch {
+	case w.entering:
+		fmt.Fprintf(w.rankTxt, "New high score, #%d! Enter your initials:", w.rank)
+	case w.rank > 0:
+		fmt.Fprintf(w.rankTxt, "You placed #%d", w.rank)
+	default:
+		fmt.Fprintf(w.rankTxt, "Not in the top %d this time", highscore.Size)
+	}
+	w.boardTxt.Clear()
+	for i, e := range w.table.Boards[w.board] {
+		if !w.entering && i+1 == w.rank {
+			w.boardTxt.Color = colornames.Yellow
+		} else {
+			w.boardTxt.Color = colornames.White
+		}
+		fmt.Fprintf(w.boardTxt, "%2d. %-3s %10.2f  %s\n", i+1, e.Initials, e.Score, e.Date.Format("2006-01-02"))
+	}
+}
+
+func (w *winScene) draw() {
+	win := w.st.win
+	// Congrats
+	drawText(win, w.congratsTxt, pixel.V(winX/2, 7*winY/8), 5)
+	// Score
+	drawText(win, w.scoreTxt, pixel.V(winX/2, 3*winY/4), 4)
+	// Timed stats
+	drawText(win, w.statsTxt, pixel.V(winX/2, 13*winY/16), 2)
+	if w.table != nil {
+		// Rank
+		drawText(win, w.rankTxt, pixel.V(winX/2, 11*winY/16), 2)
+		// Initials
+		if w.entering {
+			pos := pixel.V(winX/2-winX*40/origX, 5*winY/8)
+			r := centeredRect(pos, 60, 20)
+			box := imdraw.New(nil)
+			box.Color = colornames.White
+			box.Push(r.Min, r.Max)
+			box.Rectangle(0)
+			box.Draw(win)
+			w.initialsTxt.Clear()
+			w.initialsTxt.Color = colornames.Black
+			fmt.Fprint(w.initialsTxt, w.initials+strings.Repeat("_", maxInitials-len(w.initials)))
+			drawText(win, w.initialsTxt, pos, 3)
+			w.save.draw()
+			drawText(win, w.saveTxt, w.save.rect.Center(), 2)
+		}
+		// Board
+		drawText(win, w.boardTxt, pixel.V(winX/2, 3*winY/8), 1.75)
+	}
+	if w.entering {
+		return
+	}
+	// Seed
+	if w.board != "" && w.seed != nil {
+		drawText(win, w.seedTxt, pixel.V(winX/2, winY/20), 1.5)
+	}
+	// Menu
+	w.menu.draw()
+	drawText(win, w.menuTxt, w.menu.rect.Center(), 3)
+	// Replay
+	if w.replay != nil {
+		w.replayButton.draw()
+		drawText(win, w.replayTxt, w.replayButton.rect.Center(), 3)
+	}
+}
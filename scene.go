@@ -0,0 +1,219 @@
+package main
+
+import (
+	"fmt"
+	"github.com/dkeriazisStuy/FallacyQuest/input"
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/pixelgl"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+	"time"
+)
+
+// A scene is one screen of the game. Input is handled in update and
+// rendering in draw. onResize lays the scene out for the current window
+// size; it is also called whenever the scene becomes the top of the stack.
+type scene interface {
+	enter()
+	exit()
+	update(dt float64)
+	draw()
+	onResize()
+}
+
+// in is the input for the current frame. Scenes and widgets read it instead
+// of the window.
+var in input.Frame
+
+type sceneStack struct {
+	win    *pixelgl.Window
+	src    input.Source
+	scenes []scene
+}
+
+func (st *sceneStack) top() scene {
+	if len(st.scenes) == 0 {
+		return nil
+	}
+	return st.scenes[len(st.scenes)-1]
+}
+
+func (st *sceneStack) push(s scene) {
+	st.scenes = append(st.scenes, s)
+	s.enter()
+	s.onResize()
+}
+
+func (st *sceneStack) pop() {
+	if len(st.scenes) == 0 {
+		return
+	}
+	st.top().exit()
+	st.scenes = st.scenes[:len(st.scenes)-1]
+	if top := st.top(); top != nil {
+		top.onResize()
+	}
+}
+
+func (st *sceneStack) replace(s scene) {
+	if len(st.scenes) > 0 {
+		st.top().exit()
+		st.scenes = st.scenes[:len(st.scenes)-1]
+	}
+	st.push(s)
+}
+
+// run drives the top scene until the window closes or the stack empties.
+func (st *sceneStack) run() {
+	last := time.Now()
+	for !st.win.Closed() && len(st.scenes) > 0 {
+		dt := time.Since(last).Seconds()
+		last = time.Now()
+		in = st.src.Poll()
+		st.top().update(dt)
+		if len(st.scenes) == 0 {
+			break
+		}
+		st.win.Clear(background)
+		st.top().draw()
+		st.win.Update()
+		if resized(st.win) {
+			st.top().onResize()
+		}
+	}
+	for len(st.scenes) > 0 {
+		st.pop()
+	}
+}
+
+// backButton is the arrow in the top left corner that leaves a scene.
+func backButton(win *pixelgl.Window) (button, *imdraw.IMDraw) {
+	r := win.Bounds()
+	back := newButton(win, pixel.R(r.Min.X, r.Max.Y-winY*100/origY, r.Min.X+winX*100/origX, r.Max.Y), colornames.Sandybrown, colornames.Rosybrown)
+	backIcon := imdraw.New(nil)
+	backIcon.Push(pixel.V(r.Min.X+winX*10/origX, r.Max.Y-winY*50/origY), pixel.V(r.Min.X+winX*90/origX, r.Max.Y-winY*10/origY), pixel.V(r.Min.X+winX*90/origX, r.Max.Y-winY*90/origY))
+	backIcon.Polygon(0)
+	return back, backIcon
+}
+
+// focusList moves a focus highlight over a scene's buttons with Next and
+// Prev, so they can be pressed with Confirm.
+type focusList struct {
+	index int
+}
+
+// update moves the focus and marks the focused button. Nothing has focus
+// until Next or Prev is first pressed.
+func (f *focusList) update(buttons ...*button) {
+	n := len(buttons)
+	if n == 0 {
+		return
+	}
+	switch {
+	case in.JustPressed(input.Next) && f.index == 0:
+		f.index = 1
+	case in.JustPressed(input.Next):
+		f.index = f.index%n + 1
+	case in.JustPressed(input.Prev) && f.index <= 1:
+		f.index = n
+	case in.JustPressed(input.Prev):
+		f.index -= 1
+	}
+	for i, b := range buttons {
+		b.focused = i+1 == f.index
+	}
+}
+
+// pager pages through a list rows at a time, with buttons under the list
+// to turn the page. They are only shown when there is more than one page.
+type pager struct {
+	rows, items int
+	page        int
+	prev, next  button
+	// Text
+	prevTxt, nextTxt, pageTxt *text.Text
+}
+
+func newPager(rows, items int) pager {
+	p := pager{rows: rows, items: items}
+	p.prevTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprint(p.prevTxt, "<")
+	p.nextTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprint(p.nextTxt, ">")
+	p.pageTxt = text.New(pixel.ZV, atlas)
+	return p
+}
+
+// pages is the number of pages, at least one.
+func (p *pager) pages() int {
+	if p.items <= p.rows {
+		return 1
+	}
+	return (p.items + p.rows - 1) / p.rows
+}
+
+// span is the range of items on the current page.
+func (p *pager) span() (from, to int) {
+	from = p.page * p.rows
+	to = from + p.rows
+	if to > p.items {
+		to = p.items
+	}
+	return from, to
+}
+
+// onResize lays the buttons out below a list whose first row is at top.
+func (p *pager) onResize(win *pixelgl.Window, top float64) {
+	y := top - float64(p.rows)*winY*profileRowSize/origY
+	p.prev = newButton(win, centeredRect(pixel.V(winX/2-winX*80/origX, y), 20, 20), colornames.Sandybrown, colornames.Rosybrown)
+	p.next = newButton(win, centeredRect(pixel.V(winX/2+winX*80/origX, y), 20, 20), colornames.Sandybrown, colornames.Rosybrown)
+}
+
+// buttons are the page buttons to focus, if they are shown.
+func (p *pager) buttons() []*button {
+	if p.pages() == 1 {
+		return nil
+	}
+	return []*button{&p.prev, &p.next}
+}
+
+// update turns the page, reporting whether it did.
+func (p *pager) update() bool {
+	if p.pages() == 1 {
+		return false
+	}
+	prev, next := p.prev.check(), p.next.check()
+	switch {
+	case prev && p.page > 0:
+		p.page -= 1
+	case next && p.page < p.pages()-1:
+		p.page += 1
+	default:
+		return false
+	}
+	return true
+}
+
+func (p *pager) draw(win *pixelgl.Window) {
+	if p.pages() == 1 {
+		return
+	}
+	p.prev.draw()
+	drawText(win, p.prevTxt, p.prev.rect.Center(), 2)
+	p.next.draw()
+	drawText(win, p.nextTxt, p.next.rect.Center(), 2)
+	p.pageTxt.Clear()
+	fmt.Fprintf(p.pageTxt, "%d/%d", p.page+1, p.pages())
+	drawText(win, p.pageTxt, pixel.V(winX/2, p.prev.rect.Center().Y), 2)
+}
+
+// centeredRect is a w by h rectangle around pos, scaled to the window.
+func centeredRect(pos pixel.Vec, w, h float64) pixel.Rect {
+	return pixel.R(pos.X-winX*w/origX, pos.Y-winY*h/origY, pos.X+winX*w/origX, pos.Y+winY*h/origY)
+}
+
+// drawText draws txt centered on pos, scaled to the window.
+func drawText(win *pixelgl.Window, txt *text.Text, pos pixel.Vec, scale float64) {
+	txt.Draw(win, pixel.IM.ScaledXY(txt.Bounds().Center(), pixel.V(winX*scale/origX, winY*scale/origY)).Moved(pos.Sub(txt.Bounds().Center())))
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"github.com/dkeriazisStuy/FallacyQuest/input"
+	"github.com/faiface/pixel"
+	"testing"
+)
+
+var (
+	inside  = pixel.V(50, 50)
+	outside = pixel.V(500, 500)
+)
+
+func down(p pixel.Vec, actions ...input.Action) input.Frame {
+	f := input.Frame{Pointer: p}
+	for _, a := range actions {
+		f.Down = f.Down.With(a)
+		f.JustDown = f.JustDown.With(a)
+	}
+	return f
+}
+
+func held(p pixel.Vec, a input.Action) input.Frame {
+	return input.Frame{Pointer: p, Down: input.Set(0).With(a)}
+}
+
+func up(p pixel.Vec, a input.Action) input.Frame {
+	return input.Frame{Pointer: p, JustUp: input.Set(0).With(a)}
+}
+
+// frames polls src n times, calling each after every poll, and returns on
+// which frames each reported true.
+func frames(src input.Source, n int, each func() bool) []int {
+	var hits []int
+	for i := 0; i < n; i++ {
+		in = src.Poll()
+		if each() {
+			hits = append(hits, i)
+		}
+	}
+	return hits
+}
+
+func equal(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestButtonClick(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		frames []input.Frame
+		want   []int
+	}{
+		{"click", []input.Frame{down(inside, input.Click), held(inside, input.Click), up(inside, input.Click)}, []int{2}},
+		{"released outside", []input.Frame{down(inside, input.Click), held(outside, input.Click), up(outside, input.Click)}, nil},
+		{"pressed outside", []input.Frame{down(outside, input.Click), held(inside, input.Click), up(inside, input.Click)}, nil},
+		{"two clicks", []input.Frame{down(inside, input.Click), up(inside, input.Click), down(inside, input.Click), up(inside, input.Click)}, []int{1, 3}},
+		{"confirm without focus", []input.Frame{down(inside, input.Confirm)}, nil},
+	} {
+		b := button{rect: pixel.R(0, 0, 100, 100)}
+		src := &input.Mock{Frames: tt.frames}
+		if got := frames(src, len(tt.frames)+2, b.check); !equal(got, tt.want) {
+			t.Errorf("%s: pressed on frames %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFocusList(t *testing.T) {
+	a, b, c := button{}, button{}, button{}
+	var f focusList
+	src := &input.Mock{Frames: []input.Frame{
+		down(outside, input.Confirm),
+		down(outside, input.Next),
+		down(outside, input.Next),
+		down(outside, input.Confirm),
+		down(outside, input.Prev),
+		down(outside, input.Prev),
+		down(outside, input.Confirm),
+	}}
+	var pressed []string
+	frames(src, 8, func() bool {
+		f.update(&a, &b, &c)
+		for name, btn := range map[string]*button{"a": &a, "b": &b, "c": &c} {
+			if btn.check() {
+				pressed = append(pressed, name)
+			}
+		}
+		return false
+	})
+	// Nothing has focus until Next, then b is pressed, and Prev wraps from
+	// a round to c.
+	if len(pressed) != 2 || pressed[0] != "b" || pressed[1] != "c" {
+		t.Errorf("pressed %v, want [b c]", pressed)
+	}
+	if !c.focused || a.focused || b.focused {
+		t.Error("focus moved after the last frame")
+	}
+}
+
+func TestMockRunsOut(t *testing.T) {
+	typed := down(inside, input.Click)
+	typed.Text = "ab"
+	src := &input.Mock{Frames: []input.Frame{typed}}
+	if f := src.Poll(); f.Text != "ab" {
+		t.Errorf("got text %q, want %q", f.Text, "ab")
+	}
+	if f := src.Poll(); f.Pointer != inside || f.Down != 0 || f.JustDown != 0 || f.Text != "" {
+		t.Errorf("got %+v past the end, want an empty frame at the last pointer", f)
+	}
+}
@@ -0,0 +1,149 @@
+// Package srs schedules reviews with the SM-2 spaced repetition algorithm.
+// Every question and every fallacy has its own card, so a question comes up
+// again both when it is due and when its fallacy as a whole is weak.
+package srs
+
+import (
+	"github.com/dkeriazisStuy/FallacyQuest/content"
+	"math"
+	"math/rand"
+	"time"
+)
+
+const (
+	// MinEase is the lowest ease factor SM-2 allows.
+	MinEase = 1.3
+	// StartEase is the ease factor of a new card.
+	StartEase = 2.5
+	day       = 24 * time.Hour
+)
+
+// Card is the review state of one question or fallacy. Interval is in days.
+type Card struct {
+	Ease     float64   `json:"ease"`
+	Interval float64   `json:"interval"`
+	Reps     int       `json:"reps"`
+	Lapses   int       `json:"lapses"`
+	Due      time.Time `json:"due"`
+	Last     time.Time `json:"last"`
+}
+
+// Review updates the card after an answer of the given quality, from 0
+// (no idea) to 5 (perfect). Below 3 the card starts over and is due at once.
+func (c *Card) Review(quality int, now time.Time) {
+	if c.Ease == 0 {
+		c.Ease = StartEase
+	}
+	if quality < 3 {
+		c.Reps = 0
+		c.Interval = 0
+		c.Lapses += 1
+	} else {
+		switch c.Reps {
+		case 0:
+			c.Interval = 1
+		case 1:
+			c.Interval = 6
+		default:
+			c.Interval *= c.Ease
+		}
+		c.Reps += 1
+	}
+	miss := float64(5 - quality)
+	c.Ease = math.Max(c.Ease+.1-miss*(.08+miss*.02), MinEase)
+	c.Last = now
+	c.Due = now.Add(time.Duration(c.Interval * float64(day)))
+}
+
+// overdue is how late the card is, in multiples of its interval. New cards
+// are due now; cards not yet due are negative.
+func (c *Card) overdue(now time.Time) float64 {
+	if c == nil {
+		return 0
+	}
+	return now.Sub(c.Due).Hours() / 24 / math.Max(c.Interval, 1)
+}
+
+// Quality grades an answer for Review: first try right is perfect, each
+// further check costs a point down to 3, and wrong answers score by credit.
+func Quality(correct bool, checks int, credit float64) int {
+	if !correct {
+		return int(math.Min(credit*3, 2))
+	}
+	return int(math.Max(float64(6-checks), 3))
+}
+
+// Deck holds the cards of one player, keyed by question ID and fallacy key.
+type Deck struct {
+	Questions map[string]*Card `json:"questions"`
+	Fallacies map[string]*Card `json:"fallacies"`
+}
+
+// NewDeck returns a deck with no reviews.
+func NewDeck() *Deck {
+	return &Deck{Questions: make(map[string]*Card), Fallacies: make(map[string]*Card)}
+}
+
+func card(cards map[string]*Card, key string) *Card {
+	c := cards[key]
+	if c == nil {
+		c = &Card{Ease: StartEase}
+		cards[key] = c
+	}
+	return c
+}
+
+// Record reviews the cards for q and its fallacy.
+func (d *Deck) Record(q *content.Question, quality int, now time.Time) {
+	card(d.Questions, q.ID()).Review(quality, now)
+	card(d.Fallacies, q.Name).Review(quality, now)
+}
+
+// Seed starts the card for a fallacy from answers given before the deck
+// existed: the fewer answered right first time, the lower the ease and the
+// more overdue it is.
+func (d *Deck) Seed(fallacy string, seen, firstTry int, now time.Time) {
+	if seen == 0 || d.Fallacies[fallacy] != nil {
+		return
+	}
+	rate := float64(firstTry) / float64(seen)
+	d.Fallacies[fallacy] = &Card{
+		Ease:     MinEase + (StartEase-MinEase)*rate,
+		Interval: 1,
+		Reps:     seen,
+		Due:      now.Add(-time.Duration((1 - rate) * float64(day))),
+		Last:     now,
+	}
+}
+
+// Next picks the question most in need of review: the one whose own card
+// and fallacy card are furthest overdue. Questions whose IDs are in asked
+// are passed over unless every question has been asked. Ties are broken
+// with rng.
+func (d *Deck) Next(questions []content.Question, asked map[string]bool, now time.Time, rng *rand.Rand) *content.Question {
+	var best []*content.Question
+	bestPriority := math.Inf(-1)
+	for _, skipAsked := range []bool{true, false} {
+		for i := range questions {
+			q := &questions[i]
+			if skipAsked && asked[q.ID()] {
+				continue
+			}
+			p := d.Questions[q.ID()].overdue(now) + d.Fallacies[q.Name].overdue(now)
+			switch {
+			case p > bestPriority:
+				best = []*content.Question{q}
+				bestPriority = p
+			case p == bestPriority:
+				best = append(best, q)
+			}
+		}
+		if len(best) > 0 {
+			break
+		}
+	}
+	if len(best) == 0 {
+		return nil
+	}
+	return best[rng.Intn(len(best))]
+}
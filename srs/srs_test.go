@@ -0,0 +1,116 @@
+package srs
+
+import (
+	"github.com/dkeriazisStuy/FallacyQuest/content"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+
+func TestQuality(t *testing.T) {
+	for _, tt := range []struct {
+		correct bool
+		checks  int
+		credit  float64
+		want    int
+	}{
+		{true, 1, 1, 5},
+		{true, 2, 1, 4},
+		{true, 3, 1, 3},
+		{true, 9, 1, 3},
+		{false, 1, 0, 0},
+		{false, 2, .5, 1},
+		{false, 1, .9, 2},
+	} {
+		if got := Quality(tt.correct, tt.checks, tt.credit); got != tt.want {
+			t.Errorf("Quality(%v, %d, %g) = %d, want %d", tt.correct, tt.checks, tt.credit, got, tt.want)
+		}
+	}
+}
+
+func TestReview(t *testing.T) {
+	var c Card
+	// Perfect answers go 1 day, 6 days, then grow by the ease each time.
+	for i, want := range []float64{1, 6, 6 * 2.7, 6 * 2.7 * 2.8} {
+		c.Review(5, now)
+		if math.Abs(c.Interval-want) > 1e-9 || c.Reps != i+1 {
+			t.Fatalf("review %d: interval %g, reps %d; want %g, %d", i+1, c.Interval, c.Reps, want, i+1)
+		}
+	}
+	if want := now.Add(time.Duration(c.Interval * float64(day))); !c.Due.Equal(want) {
+		t.Errorf("due %v, want %v", c.Due, want)
+	}
+
+	// A lapse starts the card over, due at once and a little harder.
+	ease := c.Ease
+	c.Review(1, now)
+	if c.Interval != 0 || c.Reps != 0 || c.Lapses != 1 || !c.Due.Equal(now) || c.Ease >= ease {
+		t.Errorf("after a lapse: %+v", c)
+	}
+	for i := 0; i < 20; i++ {
+		c.Review(0, now)
+	}
+	if c.Ease != MinEase {
+		t.Errorf("ease %g, want it floored at %g", c.Ease, MinEase)
+	}
+	// A pass at 3 keeps the card going but lowers the ease.
+	c = Card{}
+	c.Review(3, now)
+	if c.Interval != 1 || c.Ease >= StartEase {
+		t.Errorf("after a hard pass: %+v", c)
+	}
+}
+
+func TestNext(t *testing.T) {
+	questions := []content.Question{
+		{Name: "straw", Phrases: []string{"a"}},
+		{Name: "straw", Phrases: []string{"b"}},
+		{Name: "post", Phrases: []string{"c"}},
+	}
+	rng := rand.New(rand.NewSource(1))
+	d := NewDeck()
+	// Reviewing straw well leaves post the most in need.
+	d.Record(&questions[0], 5, now)
+	d.Record(&questions[1], 5, now)
+	if q := d.Next(questions, nil, now, rng); q != &questions[2] {
+		t.Errorf("got %v, want the post question", q)
+	}
+	// The first straw question is the furthest overdue once post is asked.
+	d.Record(&questions[0], 1, now.Add(-2*day))
+	asked := map[string]bool{questions[2].ID(): true}
+	if q := d.Next(questions, asked, now, rng); q != &questions[0] {
+		t.Errorf("got %v, want the lapsed question", q)
+	}
+	// When everything has been asked they come up again.
+	for _, q := range questions {
+		asked[q.ID()] = true
+	}
+	if q := d.Next(questions, asked, now, rng); q == nil {
+		t.Error("got nothing with every question asked")
+	}
+	if q := d.Next(nil, nil, now, rng); q != nil {
+		t.Errorf("got %v from no questions", q)
+	}
+}
+
+func TestSeed(t *testing.T) {
+	d := NewDeck()
+	d.Seed("straw", 10, 10, now)
+	d.Seed("post", 10, 2, now)
+	d.Seed("cum", 0, 0, now)
+	straw, post := d.Fallacies["straw"], d.Fallacies["post"]
+	if straw == nil || post == nil || d.Fallacies["cum"] != nil {
+		t.Fatalf("seeded %v", d.Fallacies)
+	}
+	if straw.Ease != StartEase || post.Ease >= straw.Ease || post.overdue(now) <= straw.overdue(now) {
+		t.Errorf("straw %+v, post %+v; want post weaker and more overdue", straw, post)
+	}
+	// Seeding never overwrites a card.
+	d.Seed("straw", 10, 0, now)
+	if d.Fallacies["straw"] != straw {
+		t.Error("seeding replaced a card")
+	}
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/dkeriazisStuy/FallacyQuest/analytics"
+	"github.com/dkeriazisStuy/FallacyQuest/input"
+	"github.com/dkeriazisStuy/FallacyQuest/profile"
+	"github.com/dkeriazisStuy/FallacyQuest/storage"
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+	"image/color"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// statsCell is the side of a matrix cell, in original pixels.
+	statsCell = 26
+	statsTop  = 640
+	statsLeft = 300
+	// statsPairs is how many of the worst mix ups are listed.
+	statsPairs = 3
+)
+
+// statsScene shows the active player's confusion matrix: a row for each
+// right answer and a column for each fallacy chosen, plus none.
+type statsScene struct {
+	st       *sceneStack
+	matrix   *analytics.Matrix
+	columns  []string
+	export   button
+	back     button
+	backIcon *imdraw.IMDraw
+	focus    focusList
+	// Text
+	titleTxt, exportTxt, hoverTxt, mostTxt, msgTxt *text.Text
+	rowTxts, colTxts                               []*text.Text
+}
+
+func newStatsScene(st *sceneStack) *statsScene {
+	return &statsScene{st: st}
+}
+
+// confusionMatrix counts the attempts of the given profiles over the
+// catalog's fallacies.
+func confusionMatrix(profiles ...*profile.Profile) *analytics.Matrix {
+	m := analytics.NewMatrix(catalog.Keys())
+	for _, p := range profiles {
+		m.AddProfile(p)
+	}
+	return m
+}
+
+func (s *statsScene) enter() {
+	s.matrix = confusionMatrix(player)
+	s.columns = append(catalog.Keys(), analytics.None)
+	s.titleTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprintf(s.titleTxt, "Mix ups: %s", player.Name)
+	s.exportTxt = text.New(pixel.ZV, atlas)
+	fmt.Fprint(s.exportTxt, "Export CSV")
+	s.hoverTxt = text.New(pixel.ZV, atlas)
+	s.msgTxt = text.New(pixel.ZV, atlas)
+	s.msgTxt.Color = colornames.Yellow
+	s.rowTxts = nil
+	for i, f := range catalog.Fallacies {
+		txt := text.New(pixel.ZV, atlas)
+		fmt.Fprintf(txt, "%d. %s", i+1, f.Name)
+		s.rowTxts = append(s.rowTxts, txt)
+	}
+	s.colTxts = nil
+	for i := range s.columns {
+		txt := text.New(pixel.ZV, atlas)
+		fmt.Fprint(txt, i+1)
+		if s.columns[i] == analytics.None {
+			txt.Clear()
+			fmt.Fprint(txt, "-")
+		}
+		s.colTxts = append(s.colTxts, txt)
+	}
+	s.mostTxt = text.New(pixel.ZV, atlas)
+	pairs := s.matrix.Confused(statsPairs)
+	if len(pairs) == 0 {
+		fmt.Fprint(s.mostTxt, "No mix ups yet")
+	}
+	for i, p := range pairs {
+		if i > 0 {
+			fmt.Fprint(s.mostTxt, "\n")
+		}
+		fmt.Fprintf(s.mostTxt, "%s taken for %s: %d", catalog.Get(p.Answer).Name, catalog.Get(p.Chosen).Name, p.Count)
+	}
+}
+
+func (s *statsScene) exit() {}
+
+func (s *statsScene) onResize() {
+	win := s.st.win
+	s.back, s.backIcon = backButton(win)
+	s.export = newButton(win, centeredRect(pixel.V(winX-winX*130/origX, winY*50/origY), 110, 25), colornames.Green, colornames.Darkgreen)
+}
+
+// cell is the on screen rectangle of row i, column j of the matrix.
+func (s *statsScene) cell(i, j int) pixel.Rect {
+	x := winX * (statsLeft + statsCell*float64(j)) / origX
+	y := winY * (statsTop - statsCell*float64(i)) / origY
+	return pixel.R(x, y-winY*statsCell/origY, x+winX*statsCell/origX, y)
+}
+
+func (s *statsScene) update(dt float64) {
+	s.focus.update(&s.export)
+	if s.back.check() || in.JustPressed(input.Back) {
+		s.st.pop()
+		return
+	}
+	if s.export.check() {
+		s.msgTxt.Clear()
+		if path, err := s.save(); err != nil {
+			fmt.Fprint(s.msgTxt, err)
+		} else {
+			fmt.Fprintf(s.msgTxt, "Saved %s", path)
+		}
+	}
+	s.hoverTxt.Clear()
+	for i, answer := range catalog.Keys() {
+		for j, chosen := range s.columns {
+			if !s.cell(i, j).Contains(in.Pointer) {
+				continue
+			}
+			count, total := s.matrix.Count(answer, chosen), s.matrix.Total(answer)
+			name := catalog.Get(answer).Name
+			switch {
+			case chosen == answer:
+				fmt.Fprintf(s.hoverTxt, "%s chosen right %d of %d checks", name, count, total)
+			case chosen == analytics.None:
+				fmt.Fprintf(s.hoverTxt, "%s checked without a fallacy %d of %d checks", name, count, total)
+			default:
+				fmt.Fprintf(s.hoverTxt, "%s taken for %s %d of %d checks", name, catalog.Get(chosen).Name, count, total)
+			}
+		}
+	}
+}
+
+// save exports the matrix next to the other save files.
+func (s *statsScene) save() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := s.matrix.WriteCSV(&buf); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, "confusion.csv")
+	return path, storage.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// shade colors a cell: right answers in green, mix ups in red, each as
+// strong as its share of the row's checks.
+func (s *statsScene) shade(answer, chosen string) color.Color {
+	total := s.matrix.Total(answer)
+	count := s.matrix.Count(answer, chosen)
+	if total == 0 || count == 0 {
+		return colornames.Dimgray
+	}
+	share := float64(count) / float64(total)
+	if chosen == answer {
+		return pixel.RGB(0, 0.3+0.7*share, 0)
+	}
+	return pixel.RGB(0.3+0.7*share, 0, 0)
+}
+
+func (s *statsScene) draw() {
+	win := s.st.win
+	drawText(win, s.titleTxt, pixel.V(winX/2, winY*715/origY), 4)
+	im := imdraw.New(nil)
+	for i, answer := range catalog.Keys() {
+		for j, chosen := range s.columns {
+			r := s.cell(i, j)
+			im.Color = s.shade(answer, chosen)
+			im.Push(r.Min.Add(pixel.V(1, 1)), r.Max.Sub(pixel.V(1, 1)))
+			im.Rectangle(0)
+			if r.Contains(in.Pointer) {
+				im.Color = colornames.White
+				im.Push(r.Min, r.Max)
+				im.Rectangle(2)
+			}
+		}
+	}
+	im.Draw(win)
+	// Labels: numbered fallacy names down the side, the numbers along the
+	// top.
+	for i, txt := range s.rowTxts {
+		r := s.cell(i, 0)
+		drawText(win, txt, pixel.V(r.Min.X-winX*10/origX-txt.Bounds().W()*0.75*winX/origX, r.Center().Y), 1.5)
+	}
+	for j, txt := range s.colTxts {
+		r := s.cell(0, j)
+		drawText(win, txt, pixel.V(r.Center().X, r.Max.Y+winY*12/origY), 1.2)
+	}
+	bottom := s.cell(len(s.rowTxts), 0).Max.Y
+	drawText(win, s.hoverTxt, pixel.V(winX/2, bottom-winY*25/origY), 1.5)
+	drawText(win, s.mostTxt, pixel.V(winX/2, bottom-winY*70/origY), 1.5)
+	drawText(win, s.msgTxt, pixel.V(winX/2-winX*120/origX, winY*50/origY), 1.2)
+	s.export.draw()
+	drawText(win, s.exportTxt, s.export.rect.Center(), 2)
+	// Back
+	s.back.draw()
+	s.backIcon.Draw(win)
+}
+
+// exportConfusion writes the confusion matrix of every saved profile to
+// standard output as CSV.
+func exportConfusion(contentDir string) int {
+	if err := loadContent(contentDir); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	dir, err := configDir()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	players = profile.Store{Dir: filepath.Join(dir, "profiles")}
+	names, err := players.List()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	var profiles []*profile.Profile
+	for _, name := range names {
+		p, err := players.Load(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			continue
+		}
+		profiles = append(profiles, p)
+	}
+	if err := confusionMatrix(profiles...).WriteCSV(os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// entries lists the names in dir.
+func entries(t *testing.T, dir string) []string {
+	t.Helper()
+	list, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range list {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sub", "save.json")
+	for _, data := range []string{"first", "second"} {
+		if err := WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+		got, err := os.ReadFile(path)
+		if err != nil || string(got) != data {
+			t.Fatalf("read %q, %v; want %q", got, err, data)
+		}
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("got %v, %v; want mode 0600", info.Mode(), err)
+	}
+	if names := entries(t, filepath.Dir(path)); len(names) != 1 {
+		t.Errorf("left %v behind", names)
+	}
+}
+
+func TestWriteFileFails(t *testing.T) {
+	dir := t.TempDir()
+	// A directory in the way can't be renamed over, so the write fails
+	// after the temporary file is written.
+	path := filepath.Join(dir, "save.json")
+	if err := os.MkdirAll(filepath.Join(path, "keep"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(path, []byte("data"), 0644); err == nil {
+		t.Fatal("wrote over a directory")
+	}
+	if names := entries(t, dir); len(names) != 1 || names[0] != "save.json" {
+		t.Errorf("left %v behind", names)
+	}
+	if names := entries(t, path); len(names) != 1 || names[0] != "keep" {
+		t.Errorf("the directory in the way now holds %v", names)
+	}
+
+	// So can't a file where the directory should be.
+	blocker := filepath.Join(dir, "file")
+	if err := os.WriteFile(blocker, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(filepath.Join(blocker, "save.json"), []byte("data"), 0644); err == nil {
+		t.Fatal("wrote under a file")
+	}
+	if got, _ := os.ReadFile(blocker); string(got) != "old" {
+		t.Errorf("the file in the way now holds %q", got)
+	}
+}